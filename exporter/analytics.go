@@ -0,0 +1,482 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Meeting is one meeting as seen across the cache and the exported files.
+type Meeting struct {
+	ID            string
+	Title         string
+	Date          time.Time
+	HasNotes      bool
+	HasTranscript bool
+	// DurationSeconds is how long the transcript ran, or the scheduled
+	// length of the calendar event when the transcript has no timestamps.
+	// Zero when neither is known.
+	DurationSeconds int64
+	Stats           *TranscriptStats
+	// Series identifies the recurring series the meeting belongs to; see
+	// SeriesKey. Meetings from exported files only have their title to go on.
+	Series string
+	// File is the exported file relative to the output directory, if any.
+	File string
+
+	// MyNotes and AINotes are the meeting's notes as markdown.
+	MyNotes    string
+	AINotes    string
+	Transcript []TranscriptEntry
+	// Attendees are only known for meetings still in the cache.
+	Attendees []Attendee
+}
+
+// CollectMeetings gathers every meeting from the cache and from the files in
+// outputDir, including its archive folder. The cache is preferred; exported
+// files fill in transcripts the cache no longer has and meetings that are gone
+// from Granola. Meetings with neither notes nor a transcript are left out.
+// Returns meetings sorted by date.
+func CollectMeetings(state *CacheState, outputDir string) ([]Meeting, error) {
+	files, err := scanMeetingFiles(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var meetings []Meeting
+	seen := make(map[string]bool)
+	for _, doc := range state.AllDocuments() {
+		if !doc.HasExportableContent(state.Transcripts) {
+			continue
+		}
+		transcript, _ := CleanTranscript(state.Transcripts[doc.ID])
+		file := files[doc.ID]
+		if len(transcript) == 0 && file != nil {
+			transcript = file.transcript
+		}
+
+		mine, ai := FormatOptions{}.notes(&doc)
+		m := Meeting{
+			ID:            doc.ID,
+			Title:         doc.Title,
+			HasNotes:      mine != "" || ai != "",
+			HasTranscript: len(transcript) > 0,
+			Stats:         ComputeTranscriptStats(transcript),
+			Series:        SeriesKey(&doc),
+			MyNotes:       mine,
+			AINotes:       ai,
+			Transcript:    transcript,
+			Attendees:     doc.Attendees(),
+		}
+		m.Date, _ = parseTimestamp(doc.CreatedAt)
+		if m.Stats != nil {
+			m.DurationSeconds = m.Stats.DurationSeconds
+		}
+		if m.DurationSeconds == 0 {
+			m.DurationSeconds = scheduledSeconds(doc.GoogleCalendarEvent)
+		}
+		if file != nil {
+			m.File = file.RelPath
+		}
+		meetings = append(meetings, m)
+		seen[doc.ID] = true
+	}
+
+	for id, file := range files {
+		if seen[id] {
+			continue
+		}
+		m := Meeting{
+			ID:            id,
+			Title:         file.Header.Title,
+			HasNotes:      file.myNotes != "" || file.aiNotes != "",
+			HasTranscript: len(file.transcript) > 0,
+			Stats:         ComputeTranscriptStats(file.transcript),
+			File:          file.RelPath,
+			MyNotes:       file.myNotes,
+			AINotes:       file.aiNotes,
+			Transcript:    file.transcript,
+		}
+		if t, err := time.Parse("2006-01-02 15:04", file.Header.Date); err == nil {
+			m.Date = t
+		}
+		if title := NormalizeTitle(m.Title); title != "" {
+			m.Series = "title:" + title
+		}
+		if m.Stats != nil {
+			m.DurationSeconds = m.Stats.DurationSeconds
+		}
+		meetings = append(meetings, m)
+	}
+
+	resolveSeries(meetings)
+	sort.Slice(meetings, func(i, j int) bool {
+		if !meetings[i].Date.Equal(meetings[j].Date) {
+			return meetings[i].Date.Before(meetings[j].Date)
+		}
+		return meetings[i].ID < meetings[j].ID
+	})
+	return meetings, nil
+}
+
+// meetingFile is an exported file read for CollectMeetings.
+type meetingFile struct {
+	ExportedFile
+	transcript []TranscriptEntry
+	myNotes    string
+	aiNotes    string
+}
+
+// scanMeetingFiles reads the exported files in outputDir and its archive
+// folder, keyed by meeting ID. Files without a meeting ID are ignored, and
+// a missing output directory has no files.
+func scanMeetingFiles(outputDir string) (map[string]*meetingFile, error) {
+	files := make(map[string]*meetingFile)
+	for _, dir := range []string{outputDir, filepath.Join(outputDir, ArchiveDirName)} {
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		exported, err := ScanExports(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range exported {
+			id := f.Header.MeetingID
+			if id == "" || files[id] != nil {
+				continue
+			}
+			content, err := os.ReadFile(f.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f.RelPath, err)
+			}
+			rel, err := filepath.Rel(outputDir, f.Path)
+			if err != nil {
+				return nil, err
+			}
+			f.RelPath = rel
+			mine, ai := ExtractNotesFromMarkdown(string(content))
+			files[id] = &meetingFile{
+				ExportedFile: f,
+				transcript:   ExtractTranscriptFromMarkdown(string(content)),
+				myNotes:      mine,
+				aiNotes:      ai,
+			}
+		}
+	}
+	return files, nil
+}
+
+// scheduledSeconds returns the scheduled length of a timed calendar event,
+// or 0 if it isn't known.
+func scheduledSeconds(event *CalendarEvent) int64 {
+	if event == nil || event.Start == nil || event.End == nil {
+		return 0
+	}
+	start, ok := parseTimestamp(event.Start.DateTime)
+	if !ok {
+		return 0
+	}
+	end, ok := parseTimestamp(event.End.DateTime)
+	if !ok || !end.After(start) {
+		return 0
+	}
+	return int64(end.Sub(start).Seconds())
+}
+
+// StatsOptions configures ComputeArchiveStats.
+type StatsOptions struct {
+	// Since and Until limit the meetings to a range of whole days, both
+	// included. Zero values leave the range open.
+	Since time.Time
+	Until time.Time
+	// Top is how many of the most frequent series to list.
+	Top int
+}
+
+// DefaultStatsTop is the default number of series listed by granary stats.
+const DefaultStatsTop = 10
+
+// ArchiveStats aggregates meetings over time. Talk ratios are the share of
+// speech that was yours ("Me"), by talk time when transcripts have
+// timestamps and by words otherwise.
+type ArchiveStats struct {
+	Since              string        `json:"since,omitempty"`
+	Until              string        `json:"until,omitempty"`
+	Meetings           int           `json:"meetings"`
+	WithTranscript     int           `json:"with_transcript"`
+	NotesOnly          int           `json:"notes_only"`
+	TranscriptCoverage float64       `json:"transcript_coverage"`
+	MeetingHours       float64       `json:"meeting_hours"`
+	TalkRatio          float64       `json:"talk_ratio"`
+	Weeks              []WeekStats   `json:"weeks"`
+	Series             []SeriesStats `json:"series"`
+}
+
+// WeekStats are the totals for the week starting on Monday Week.
+type WeekStats struct {
+	Week           string  `json:"week"`
+	Meetings       int     `json:"meetings"`
+	WithTranscript int     `json:"with_transcript"`
+	MeetingHours   float64 `json:"meeting_hours"`
+	TalkRatio      float64 `json:"talk_ratio"`
+}
+
+// SeriesStats describes a meeting title or calendar series that came up
+// more than once.
+type SeriesStats struct {
+	Title     string `json:"title"`
+	Meetings  int    `json:"meetings"`
+	Recurring bool   `json:"recurring"`
+	First     string `json:"first"`
+	Last      string `json:"last"`
+}
+
+// talkTotals accumulates talk time and words, for the user and for everyone.
+type talkTotals struct {
+	mySeconds, seconds int64
+	myWords, words     int
+}
+
+func (t *talkTotals) add(stats *TranscriptStats) {
+	if stats == nil {
+		return
+	}
+	for _, s := range stats.Speakers {
+		t.seconds += s.TalkSeconds
+		t.words += s.Words
+		if s.Source == "microphone" {
+			t.mySeconds += s.TalkSeconds
+			t.myWords += s.Words
+		}
+	}
+}
+
+// ratio returns the user's share of talk time, or of words without timestamps.
+func (t talkTotals) ratio() float64 {
+	if t.seconds > 0 {
+		return roundTo(float64(t.mySeconds)/float64(t.seconds), 3)
+	}
+	if t.words > 0 {
+		return roundTo(float64(t.myWords)/float64(t.words), 3)
+	}
+	return 0
+}
+
+// ComputeArchiveStats aggregates meetings, which must be sorted by date, into
+// weekly and overall statistics. Meetings without a known date are only
+// included when no range is given.
+func ComputeArchiveStats(meetings []Meeting, opts StatsOptions) *ArchiveStats {
+	if opts.Top <= 0 {
+		opts.Top = DefaultStatsTop
+	}
+
+	stats := &ArchiveStats{Weeks: []WeekStats{}, Series: []SeriesStats{}}
+	if !opts.Since.IsZero() {
+		stats.Since = opts.Since.Format("2006-01-02")
+	}
+	if !opts.Until.IsZero() {
+		stats.Until = opts.Until.Format("2006-01-02")
+	}
+
+	var selected []Meeting
+	for _, m := range meetings {
+		if opts.inRange(m.Date) {
+			selected = append(selected, m)
+		}
+	}
+
+	var talk talkTotals
+	var seconds int64
+	series := make(map[string]*SeriesStats)
+	for _, m := range selected {
+		stats.Meetings++
+		if m.HasTranscript {
+			stats.WithTranscript++
+		} else if m.HasNotes {
+			stats.NotesOnly++
+		}
+		seconds += m.DurationSeconds
+		talk.add(m.Stats)
+
+		if m.Series == "" {
+			continue
+		}
+		s := series[m.Series]
+		if s == nil {
+			s = &SeriesStats{Recurring: strings.HasPrefix(m.Series, "event:")}
+			series[m.Series] = s
+		}
+		s.Meetings++
+		// Meetings are in date order, so the latest title wins
+		s.Title = m.Title
+		if s.First == "" {
+			s.First = formatMeetingDay(m.Date)
+		}
+		s.Last = formatMeetingDay(m.Date)
+	}
+
+	if stats.Meetings > 0 {
+		stats.TranscriptCoverage = roundTo(float64(stats.WithTranscript)/float64(stats.Meetings), 3)
+	}
+	stats.MeetingHours = roundTo(float64(seconds)/3600, 1)
+	stats.TalkRatio = talk.ratio()
+	stats.Weeks = weeklyStats(selected)
+
+	for _, s := range series {
+		if s.Meetings > 1 {
+			stats.Series = append(stats.Series, *s)
+		}
+	}
+	sort.Slice(stats.Series, func(i, j int) bool {
+		if stats.Series[i].Meetings != stats.Series[j].Meetings {
+			return stats.Series[i].Meetings > stats.Series[j].Meetings
+		}
+		return stats.Series[i].Title < stats.Series[j].Title
+	})
+	if len(stats.Series) > opts.Top {
+		stats.Series = stats.Series[:opts.Top]
+	}
+
+	return stats
+}
+
+// inRange reports whether a meeting on date falls within the options' range.
+func (o StatsOptions) inRange(date time.Time) bool {
+	if o.Since.IsZero() && o.Until.IsZero() {
+		return true
+	}
+	if date.IsZero() || (!o.Since.IsZero() && date.Before(o.Since)) {
+		return false
+	}
+	// Until is a day, so the range ends when the next day starts
+	return o.Until.IsZero() || date.Before(o.Until.AddDate(0, 0, 1))
+}
+
+// weeklyStats builds one entry per week from the first to the last week with
+// a meeting, including quiet weeks so trends read correctly. Meetings without
+// a date are left out.
+func weeklyStats(meetings []Meeting) []WeekStats {
+	type week struct {
+		stats   WeekStats
+		seconds int64
+		talk    talkTotals
+	}
+	byStart := make(map[time.Time]*week)
+	var first, last time.Time
+	for _, m := range meetings {
+		if m.Date.IsZero() {
+			continue
+		}
+		start := weekStart(m.Date)
+		w := byStart[start]
+		if w == nil {
+			w = &week{}
+			byStart[start] = w
+		}
+		w.stats.Meetings++
+		if m.HasTranscript {
+			w.stats.WithTranscript++
+		}
+		w.seconds += m.DurationSeconds
+		w.talk.add(m.Stats)
+
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+
+	result := []WeekStats{}
+	if first.IsZero() {
+		return result
+	}
+	for start := first; !start.After(last); start = start.AddDate(0, 0, 7) {
+		ws := WeekStats{Week: start.Format("2006-01-02")}
+		if w := byStart[start]; w != nil {
+			ws.Meetings = w.stats.Meetings
+			ws.WithTranscript = w.stats.WithTranscript
+			ws.MeetingHours = roundTo(float64(w.seconds)/3600, 1)
+			ws.TalkRatio = w.talk.ratio()
+		}
+		result = append(result, ws)
+	}
+	return result
+}
+
+// weekStart returns midnight UTC on the Monday of t's week.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// formatMeetingDay formats a meeting's date as "YYYY-MM-DD", or "" if unknown.
+func formatMeetingDay(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// roundTo rounds f to the given number of decimal places.
+func roundTo(f float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(f*scale) / scale
+}
+
+// WriteTable writes the statistics as plain text tables.
+func (s *ArchiveStats) WriteTable(w io.Writer) {
+	switch {
+	case s.Since != "" && s.Until != "":
+		fmt.Fprintf(w, "Meetings from %s until %s\n\n", s.Since, s.Until)
+	case s.Since != "":
+		fmt.Fprintf(w, "Meetings since %s\n\n", s.Since)
+	case s.Until != "":
+		fmt.Fprintf(w, "Meetings until %s\n\n", s.Until)
+	}
+
+	fmt.Fprintf(w, "Meetings:        %s\n", NumberWithCommas(s.Meetings))
+	fmt.Fprintf(w, "Meeting hours:   %.1f\n", s.MeetingHours)
+	fmt.Fprintf(w, "With transcript: %s (%.0f%%)\n", NumberWithCommas(s.WithTranscript), s.TranscriptCoverage*100)
+	fmt.Fprintf(w, "Notes only:      %s\n", NumberWithCommas(s.NotesOnly))
+	fmt.Fprintf(w, "My talk share:   %.0f%%\n", s.TalkRatio*100)
+
+	if len(s.Weeks) > 0 {
+		fmt.Fprintln(w, "\nWeekly:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  Week of\tMeetings\tHours\tTranscripts\tMy talk share")
+		for _, week := range s.Weeks {
+			share := "-"
+			if week.TalkRatio > 0 {
+				share = fmt.Sprintf("%.0f%%", week.TalkRatio*100)
+			}
+			fmt.Fprintf(tw, "  %s\t%d\t%.1f\t%d\t%s\n", week.Week, week.Meetings, week.MeetingHours, week.WithTranscript, share)
+		}
+		tw.Flush()
+	}
+
+	if len(s.Series) > 0 {
+		fmt.Fprintln(w, "\nMost frequent:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  Title\tMeetings\tFirst\tLast")
+		for _, series := range s.Series {
+			title := series.Title
+			if series.Recurring {
+				title += " (recurring)"
+			}
+			fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\n", title, series.Meetings, series.First, series.Last)
+		}
+		tw.Flush()
+	}
+}
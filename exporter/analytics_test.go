@@ -0,0 +1,177 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectMeetings(t *testing.T) {
+	t.Run("combines the cache with exported and archived files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {
+					ID: "doc1", Title: "Sync", CreatedAt: "2026-01-19T10:00:00Z", NotesMarkdown: "Some notes here",
+					GoogleCalendarEvent: &CalendarEvent{
+						RecurringEventID: "series",
+						Start:            &CalendarTime{DateTime: "2026-01-19T10:00:00Z"},
+						End:              &CalendarTime{DateTime: "2026-01-19T10:30:00Z"},
+					},
+				},
+				"doc2": {ID: "doc2", Title: "Notes only", CreatedAt: "2026-01-20T10:00:00Z", NotesMarkdown: "Plenty of notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		// doc1's transcript only survives in its exported file
+		preserved := FormatDocumentMarkdown(&Document{ID: "doc1", Title: "Sync", CreatedAt: "2026-01-19T10:00:00Z"},
+			[]TranscriptEntry{{Source: "microphone", Text: "Hello everyone"}})
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-19_Sync.md"), preserved)
+		deleted := FormatDocumentMarkdown(&Document{ID: "old", Title: "Sync", CreatedAt: "2026-01-12T10:00:00Z"},
+			[]TranscriptEntry{{Source: "system", Text: "Last week"}})
+		writeTestFile(t, filepath.Join(tmpDir, ArchiveDirName, "2026-01-12_Sync.md"), deleted)
+
+		meetings, err := CollectMeetings(state, tmpDir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(meetings) != 3 {
+			t.Fatalf("Expected 3 meetings, got %+v", meetings)
+		}
+
+		old, sync, notes := meetings[0], meetings[1], meetings[2]
+		if old.ID != "old" || !old.HasTranscript || old.File != filepath.Join(ArchiveDirName, "2026-01-12_Sync.md") {
+			t.Errorf("Unexpected archived meeting: %+v", old)
+		}
+		if old.Series != "event:series" {
+			t.Errorf("Expected archived meeting to join the calendar series, got %q", old.Series)
+		}
+		if sync.ID != "doc1" || !sync.HasTranscript || sync.Stats == nil || sync.Stats.Words != 2 {
+			t.Errorf("Expected preserved transcript for doc1, got %+v", sync)
+		}
+		if sync.DurationSeconds != 1800 {
+			t.Errorf("Expected scheduled duration without timestamps, got %d", sync.DurationSeconds)
+		}
+		if notes.HasTranscript || !notes.HasNotes {
+			t.Errorf("Unexpected notes-only meeting: %+v", notes)
+		}
+	})
+
+	t.Run("works without an output directory", func(t *testing.T) {
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-19T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+		}
+		meetings, err := CollectMeetings(state, filepath.Join(t.TempDir(), "missing"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(meetings) != 1 {
+			t.Errorf("Expected 1 meeting, got %+v", meetings)
+		}
+	})
+}
+
+func TestComputeArchiveStats(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d.Add(10 * time.Hour)
+	}
+	talk := func(me, them int64) *TranscriptStats {
+		return &TranscriptStats{Speakers: []SpeakerStats{
+			{Source: "microphone", TalkSeconds: me, Words: 1},
+			{Source: "system", TalkSeconds: them, Words: 1},
+		}}
+	}
+	meetings := []Meeting{
+		{ID: "a", Title: "Sync", Date: day("2026-01-05"), HasTranscript: true, DurationSeconds: 3600, Stats: talk(30, 90), Series: "event:s"},
+		{ID: "b", Title: "Planning", Date: day("2026-01-07"), HasNotes: true, DurationSeconds: 1800, Series: "title:planning"},
+		{ID: "c", Title: "Weekly Sync", Date: day("2026-01-19"), HasTranscript: true, DurationSeconds: 1800, Stats: talk(60, 60), Series: "event:s"},
+	}
+
+	t.Run("aggregates overall and per week", func(t *testing.T) {
+		stats := ComputeArchiveStats(meetings, StatsOptions{})
+
+		if stats.Meetings != 3 || stats.WithTranscript != 2 || stats.NotesOnly != 1 {
+			t.Errorf("Unexpected counts: %+v", stats)
+		}
+		if stats.TranscriptCoverage != 0.667 || stats.MeetingHours != 2 || stats.TalkRatio != 0.375 {
+			t.Errorf("Unexpected totals: %+v", stats)
+		}
+
+		if len(stats.Weeks) != 3 {
+			t.Fatalf("Expected 3 weeks including the quiet one, got %+v", stats.Weeks)
+		}
+		first, quiet, last := stats.Weeks[0], stats.Weeks[1], stats.Weeks[2]
+		if first.Week != "2026-01-05" || first.Meetings != 2 || first.MeetingHours != 1.5 || first.TalkRatio != 0.25 {
+			t.Errorf("Unexpected first week: %+v", first)
+		}
+		if quiet.Week != "2026-01-12" || quiet.Meetings != 0 {
+			t.Errorf("Unexpected quiet week: %+v", quiet)
+		}
+		if last.Week != "2026-01-19" || last.TalkRatio != 0.5 {
+			t.Errorf("Unexpected last week: %+v", last)
+		}
+
+		if len(stats.Series) != 1 {
+			t.Fatalf("Expected only repeated series, got %+v", stats.Series)
+		}
+		series := stats.Series[0]
+		if series.Title != "Weekly Sync" || series.Meetings != 2 || !series.Recurring || series.First != "2026-01-05" || series.Last != "2026-01-19" {
+			t.Errorf("Unexpected series: %+v", series)
+		}
+	})
+
+	t.Run("limits to a date range", func(t *testing.T) {
+		stats := ComputeArchiveStats(meetings, StatsOptions{Since: day("2026-01-06").Truncate(24 * time.Hour), Until: day("2026-01-19").Truncate(24 * time.Hour)})
+
+		// The meeting on the until day itself is included
+		if stats.Meetings != 2 || stats.Since != "2026-01-06" || stats.Until != "2026-01-19" {
+			t.Errorf("Unexpected range stats: %+v", stats)
+		}
+		if len(stats.Series) != 0 {
+			t.Errorf("Expected no repeated series in range, got %+v", stats.Series)
+		}
+	})
+
+	t.Run("prints the until date as given", func(t *testing.T) {
+		stats := ComputeArchiveStats(meetings, StatsOptions{Until: day("2026-01-07").Truncate(24 * time.Hour)})
+
+		var buf bytes.Buffer
+		stats.WriteTable(&buf)
+		if !strings.Contains(buf.String(), "Meetings until 2026-01-07\n") {
+			t.Errorf("Expected the until date in:\n%s", buf.String())
+		}
+
+		data, err := json.Marshal(stats)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"until":"2026-01-07"`) {
+			t.Errorf("Expected the until date in %s", data)
+		}
+		if stats.Meetings != 2 {
+			t.Errorf("Expected both meetings up to the until day, got %d", stats.Meetings)
+		}
+	})
+
+	t.Run("writes a table", func(t *testing.T) {
+		var buf bytes.Buffer
+		ComputeArchiveStats(meetings, StatsOptions{}).WriteTable(&buf)
+
+		out := buf.String()
+		for _, want := range []string{"Meetings:        3", "With transcript: 2 (67%)", "Weekly:", "2026-01-12", "Weekly Sync (recurring)"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected %q in:\n%s", want, out)
+			}
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package exporter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ArchiveDirName is the folder inside the output directory that pruned files are moved to.
+const ArchiveDirName = "_archive"
+
+// ExportedFile describes a markdown file found in the output directory.
+type ExportedFile struct {
+	Path          string
+	RelPath       string
+	Header        ExportHeader
+	HasTranscript bool
+}
+
+// ScanExports walks the output directory and returns every markdown file,
+// sorted by relative path. Hidden directories, the archive and series index
+// folders, and index pages are skipped.
+func ScanExports(dir string) ([]ExportedFile, error) {
+	var files []ExportedFile
+
+	err := walkExports(dir, func(path, rel string) error {
+		f, ok, err := readExport(path, rel)
+		if ok {
+			files = append(files, f)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].RelPath < files[j].RelPath
+	})
+
+	return files, nil
+}
+
+// walkExports calls fn for every markdown file ScanExports looks at, without
+// reading it. rel is the file's path relative to dir.
+func walkExports(dir string, fn func(path, rel string) error) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == ArchiveDirName || d.Name() == SeriesDirName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".md") || !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return fn(path, rel)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan output directory: %w", err)
+	}
+	return nil
+}
+
+// readExport reads the header of an exported file. Reports false for index
+// pages.
+func readExport(path, rel string) (ExportedFile, bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ExportedFile{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if strings.HasPrefix(string(content), IndexMarker) {
+		return ExportedFile{}, false, nil
+	}
+	return ExportedFile{
+		Path:          path,
+		RelPath:       rel,
+		Header:        ExtractHeaderFromMarkdown(string(content)),
+		HasTranscript: strings.Contains(string(content), "## Transcript"),
+	}, true, nil
+}
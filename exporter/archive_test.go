@@ -0,0 +1,58 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanExports(t *testing.T) {
+	t.Run("reads headers from exported files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		doc := &Document{ID: "doc1", Title: "Kickoff", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Notes"}
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-21_Kickoff.md"), FormatDocumentMarkdown(doc, []TranscriptEntry{{Text: "Hi", Source: "microphone"}}))
+		writeTestFile(t, filepath.Join(tmpDir, "Customers", "2026-01-22_Other.md"), "# Other\nDate: 2026-01-22 10:00\nMeeting ID: doc2\n\n---\n")
+
+		files, err := ScanExports(tmpDir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(files) != 2 {
+			t.Fatalf("Expected 2 files, got %d", len(files))
+		}
+		if files[0].RelPath != "2026-01-21_Kickoff.md" || files[0].Header.MeetingID != "doc1" {
+			t.Errorf("Unexpected first file: %+v", files[0])
+		}
+		if !files[0].HasTranscript {
+			t.Error("Expected first file to have a transcript")
+		}
+		if files[1].RelPath != filepath.Join("Customers", "2026-01-22_Other.md") || files[1].HasTranscript {
+			t.Errorf("Unexpected second file: %+v", files[1])
+		}
+	})
+
+	t.Run("skips archive, hidden directories and other files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, ArchiveDirName, "old.md"), "# Old\nMeeting ID: old\n")
+		writeTestFile(t, filepath.Join(tmpDir, ".git", "notes.md"), "# Hidden\n")
+		writeTestFile(t, filepath.Join(tmpDir, "state.json"), "{}")
+
+		files, err := ScanExports(tmpDir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(files) != 0 {
+			t.Errorf("Expected no files, got %+v", files)
+		}
+	})
+}
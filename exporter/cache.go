@@ -1,28 +1,57 @@
 package exporter
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+)
+
+// errCacheFieldEmpty and errUnexpectedShape mark well-formed JSON that doesn't
+// have the structure of a Granola cache.
+var (
+	errCacheFieldEmpty = errors.New("cache field is empty")
+	errUnexpectedShape = errors.New("unexpected cache structure")
 )
 
 // cacheVersionRegex extracts the version number from cache-vN.json filenames.
 var cacheVersionRegex = regexp.MustCompile(`cache-v(\d+)\.json$`)
 
-// FindCacheFile finds the latest Granola cache file.
-// Returns the path to the cache file with the highest version number.
-func FindCacheFile() (string, error) {
+// GranolaDir returns the directory where Granola keeps its cache files.
+func GranolaDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
+	return filepath.Join(homeDir, "Library", "Application Support", "Granola"), nil
+}
+
+// IsCacheFile reports whether path names a Granola cache-vN.json file.
+func IsCacheFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, "cache-v") && cacheVersionRegex.MatchString(base)
+}
 
-	granolaDir := filepath.Join(homeDir, "Library", "Application Support", "Granola")
+// FindCacheFile finds the latest Granola cache file.
+// Returns the path to the cache file with the highest version number.
+func FindCacheFile() (string, error) {
+	granolaDir, err := GranolaDir()
+	if err != nil {
+		return "", err
+	}
+	return FindCacheFileIn(granolaDir)
+}
 
+// FindCacheFileIn finds the cache file with the highest version number in granolaDir.
+func FindCacheFileIn(granolaDir string) (string, error) {
 	// Find all cache-v*.json files
 	pattern := filepath.Join(granolaDir, "cache-v*.json")
 	matches, err := filepath.Glob(pattern)
@@ -52,6 +81,12 @@ func FindCacheFile() (string, error) {
 	return latestCache, nil
 }
 
+// CacheVersion returns the version number encoded in a cache filename,
+// or 0 if the filename does not follow the cache-vN.json pattern.
+func CacheVersion(path string) int {
+	return extractVersion(path)
+}
+
 // extractVersion extracts the version number from a cache filename.
 func extractVersion(path string) int {
 	matches := cacheVersionRegex.FindStringSubmatch(path)
@@ -62,73 +97,234 @@ func extractVersion(path string) int {
 	return v
 }
 
-// outerCacheRaw is used to detect whether the "cache" field is a string or object.
-type outerCacheRaw struct {
-	Cache json.RawMessage `json:"cache"`
-}
-
-// innerCache represents the inner JSON structure containing the state.
-type innerCache struct {
-	State CacheState `json:"state"`
-}
-
 // LoadCache loads and parses the Granola cache from a file path.
+// The file is streamed rather than read into memory up front, except for
+// legacy caches; see ParseCacheReader.
 func LoadCache(path string) (*CacheState, error) {
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read cache file: %w", err)
 	}
+	defer f.Close()
 
-	return ParseCache(data)
+	return ParseCacheReader(bufio.NewReaderSize(f, 1<<20))
 }
 
 // ParseCache parses the Granola cache from raw JSON bytes.
+// See ParseCacheReader for the supported formats.
+func ParseCache(data []byte) (*CacheState, error) {
+	return ParseCacheReader(bytes.NewReader(data))
+}
+
+// ParseCacheReader parses the Granola cache from a stream.
 // Supports two formats:
 //   - Legacy (cache-v5 and earlier): "cache" is a JSON string containing nested JSON
 //   - Current (cache-v6+): "cache" is a direct JSON object
-func ParseCache(data []byte) (*CacheState, error) {
-	var outer outerCacheRaw
-	if err := json.Unmarshal(data, &outer); err != nil {
+//
+// The JSON is walked token by token so that only documents, sharedDocuments,
+// transcripts, the folder lists and the notes panels are materialized; every
+// other value is skipped. Legacy caches are still held in memory whole: the
+// decoder has to read the entire "cache" string before its contents can be
+// walked, so they take about twice the file size while parsing.
+func ParseCacheReader(r io.Reader) (*CacheState, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
 		return nil, fmt.Errorf("failed to parse cache JSON: %w", err)
 	}
 
-	if len(outer.Cache) == 0 {
-		return nil, fmt.Errorf("cache field is empty")
-	}
+	var state *CacheState
+	for dec.More() {
+		key, err := readKey(dec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cache JSON: %w", err)
+		}
+		if key != "cache" || state != nil {
+			if err := skipValue(dec); err != nil {
+				return nil, fmt.Errorf("failed to parse cache JSON: %w", err)
+			}
+			continue
+		}
 
-	var innerData []byte
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cache JSON: %w", unexpectedEOF(err))
+		}
 
-	// If cache is a JSON string, unwrap it; otherwise use it directly
-	if outer.Cache[0] == '"' {
-		var cacheStr string
-		if err := json.Unmarshal(outer.Cache, &cacheStr); err != nil {
-			return nil, fmt.Errorf("failed to parse cache string: %w", err)
+		switch v := tok.(type) {
+		case string:
+			// Legacy format: the inner JSON is encoded as a string, which the
+			// decoder has already read into memory in full
+			if v == "" {
+				return nil, errCacheFieldEmpty
+			}
+			inner := json.NewDecoder(strings.NewReader(v))
+			if err := expectDelim(inner, '{'); err != nil {
+				return nil, fmt.Errorf("failed to parse inner cache JSON: %w", err)
+			}
+			state, err = decodeInner(inner)
+		case json.Delim:
+			if v != '{' {
+				return nil, fmt.Errorf("failed to parse cache JSON: %w: unexpected %q for cache field", errUnexpectedShape, v)
+			}
+			state, err = decodeInner(dec)
+		default:
+			return nil, errCacheFieldEmpty
 		}
-		if cacheStr == "" {
-			return nil, fmt.Errorf("cache field is empty")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inner cache JSON: %w", err)
 		}
-		innerData = []byte(cacheStr)
-	} else {
-		innerData = outer.Cache
 	}
 
-	var inner innerCache
-	if err := json.Unmarshal(innerData, &inner); err != nil {
-		return nil, fmt.Errorf("failed to parse inner cache JSON: %w", err)
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, fmt.Errorf("failed to parse cache JSON: %w", err)
+	}
+
+	if state == nil {
+		return nil, errCacheFieldEmpty
 	}
 
 	// Ensure maps are initialized
-	if inner.State.Documents == nil {
-		inner.State.Documents = make(map[string]Document)
+	if state.Documents == nil {
+		state.Documents = make(map[string]Document)
+	}
+	if state.SharedDocuments == nil {
+		state.SharedDocuments = make(map[string]Document)
+	}
+	if state.Transcripts == nil {
+		state.Transcripts = make(map[string][]TranscriptEntry)
+	}
+	state.assignFolders()
+	state.assignPanels()
+
+	return state, nil
+}
+
+// decodeInner reads the inner cache object, whose opening brace has already
+// been consumed, and decodes the fields of its "state" object.
+func decodeInner(dec *json.Decoder) (*CacheState, error) {
+	state := &CacheState{}
+
+	for dec.More() {
+		key, err := readKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		if key != "state" {
+			if err := skipValue(dec); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+		if err := decodeStateFields(dec, state); err != nil {
+			return nil, err
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// decodeStateFields decodes the state keys granary uses and skips the rest.
+func decodeStateFields(dec *json.Decoder, state *CacheState) error {
+	for dec.More() {
+		key, err := readKey(dec)
+		if err != nil {
+			return err
+		}
+
+		var target any
+		switch key {
+		case "documents":
+			target = &state.Documents
+		case "sharedDocuments":
+			target = &state.SharedDocuments
+		case "transcripts":
+			target = &state.Transcripts
+		case "documentLists":
+			target = &state.DocumentLists
+		case "documentListsMetadata":
+			target = &state.DocumentListsMetadata
+		case "documentPanels":
+			target = &state.DocumentPanels
+		default:
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := dec.Decode(target); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", key, unexpectedEOF(err))
+		}
+	}
+	return nil
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("%w: expected %q but found %v", errUnexpectedShape, want, tok)
+	}
+	return nil
+}
+
+// readKey reads an object key.
+func readKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", unexpectedEOF(err)
 	}
-	if inner.State.SharedDocuments == nil {
-		inner.State.SharedDocuments = make(map[string]Document)
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: expected object key but found %v", errUnexpectedShape, tok)
 	}
-	if inner.State.Transcripts == nil {
-		inner.State.Transcripts = make(map[string][]TranscriptEntry)
+	return key, nil
+}
+
+// skipValue consumes the next value without materializing it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
 	}
+}
 
-	return &inner.State, nil
+// unexpectedEOF converts io.EOF, which the decoder returns when input ends
+// between tokens, into io.ErrUnexpectedEOF since a value was still expected.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
 }
 
 // GetCacheSize returns the size of a file in bytes.
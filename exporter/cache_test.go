@@ -1,6 +1,13 @@
 package exporter
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -155,6 +162,37 @@ func TestParseCache(t *testing.T) {
 		}
 	})
 
+	t.Run("skips unrelated keys at every level", func(t *testing.T) {
+		data := []byte(`{
+			"version": 6,
+			"cache": {"version":1,"state":{"documentPanels":{"doc1":{"p1":{"content":{"type":"doc","content":[{"type":"text","text":"{[\"}"}]}}}},"documents":{"doc1":{"id":"doc1","title":"Test","extra":[1,{"a":null}]}},"events":[],"transcripts":{}},"other":"x"},
+			"trailer": [true, false, null]
+		}`)
+
+		state, err := ParseCache(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state.Documents["doc1"].Title != "Test" {
+			t.Errorf("Expected title 'Test', got %q", state.Documents["doc1"].Title)
+		}
+	})
+
+	t.Run("returns unexpected EOF for truncated cache", func(t *testing.T) {
+		data := syntheticCache(5, false)
+		_, err := ParseCache(data[:len(data)/2])
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("returns error for missing cache field", func(t *testing.T) {
+		_, err := ParseCache([]byte(`{"version": 6}`))
+		if err == nil || !strings.Contains(err.Error(), "cache field is empty") {
+			t.Errorf("Expected empty cache error, got %v", err)
+		}
+	})
+
 	t.Run("returns error for invalid outer JSON", func(t *testing.T) {
 		data := []byte(`not valid json`)
 
@@ -187,6 +225,115 @@ func TestParseCache(t *testing.T) {
 	})
 }
 
+func TestLoadCache(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("legacy=%v", legacy), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cache-v6.json")
+			if err := os.WriteFile(path, syntheticCache(20, legacy), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := LoadCache(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(state.Documents) != 20 {
+				t.Errorf("Expected 20 documents, got %d", len(state.Documents))
+			}
+			if len(state.Transcripts) != 20 {
+				t.Errorf("Expected 20 transcripts, got %d", len(state.Transcripts))
+			}
+		})
+	}
+}
+
+// syntheticCache builds a cache file with n documents, each with a transcript
+// and a large notes panel, in either the legacy or current format.
+func syntheticCache(n int, legacy bool) []byte {
+	documents := make(map[string]Document, n)
+	transcripts := make(map[string][]TranscriptEntry, n)
+	panels := make(map[string]any, n)
+
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("doc-%06d", i)
+		documents[id] = Document{
+			ID:            id,
+			Title:         fmt.Sprintf("Meeting %d", i),
+			CreatedAt:     "2026-01-21T10:00:00Z",
+			NotesMarkdown: strings.Repeat("- discussed the roadmap\n", 20),
+		}
+		for j := 0; j < 50; j++ {
+			transcripts[id] = append(transcripts[id], TranscriptEntry{
+				ID:             fmt.Sprintf("%s-t%d", id, j),
+				DocumentID:     id,
+				StartTimestamp: "2026-01-21T10:00:00Z",
+				EndTimestamp:   "2026-01-21T10:00:05Z",
+				Text:           "This is a transcript fragment with a handful of words.",
+				Source:         "system",
+				IsFinal:        true,
+			})
+		}
+		panels[id] = map[string]any{
+			"panel-1": map[string]any{
+				"id":               "panel-1",
+				"title":            "Summary",
+				"content":          map[string]any{"type": "doc", "content": []any{map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": strings.Repeat("panel ", 500)}}}}},
+				"original_content": strings.Repeat("<p>html</p>", 200),
+			},
+		}
+	}
+
+	inner := map[string]any{
+		"state": map[string]any{
+			"documents":      documents,
+			"transcripts":    transcripts,
+			"documentPanels": panels,
+		},
+	}
+
+	var cache any = inner
+	if legacy {
+		encoded, _ := json.Marshal(inner)
+		cache = string(encoded)
+	}
+
+	data, _ := json.Marshal(map[string]any{"cache": cache, "version": 6})
+	return data
+}
+
+func BenchmarkParseCache(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		for _, legacy := range []bool{false, true} {
+			data := syntheticCache(n, legacy)
+			b.Run(fmt.Sprintf("docs=%d/legacy=%v", n, legacy), func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := ParseCache(data); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkLoadCache(b *testing.B) {
+	data := syntheticCache(1000, false)
+	path := filepath.Join(b.TempDir(), "cache-v6.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := LoadCache(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestAllDocuments(t *testing.T) {
 	t.Run("merges owned and shared documents", func(t *testing.T) {
 		state := &CacheState{
@@ -229,6 +376,23 @@ func TestAllDocuments(t *testing.T) {
 		}
 	})
 
+	t.Run("leaves out deleted documents", func(t *testing.T) {
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Kept"},
+				"doc2": {ID: "doc2", Title: "Trashed", DeletedAt: "2026-01-22T10:00:00Z"},
+			},
+		}
+
+		all := state.AllDocuments()
+		if len(all) != 1 {
+			t.Errorf("Expected 1 document, got %d", len(all))
+		}
+		if _, ok := all["doc2"]; ok {
+			t.Error("Deleted document should be left out")
+		}
+	})
+
 	t.Run("handles nil shared documents", func(t *testing.T) {
 		state := &CacheState{
 			Documents: map[string]Document{
@@ -243,6 +407,232 @@ func TestAllDocuments(t *testing.T) {
 	})
 }
 
+func TestParseCacheMetadata(t *testing.T) {
+	t.Run("decodes document metadata", func(t *testing.T) {
+		data := []byte(`{"cache": {"state": {"documents": {"doc1": {
+			"id": "doc1",
+			"title": "Kickoff",
+			"created_at": "2026-01-21T10:00:00Z",
+			"updated_at": "2026-01-21T11:00:00Z",
+			"deleted_at": null,
+			"workspace_id": "ws-1",
+			"people": {
+				"creator": {"name": "Alice Smith", "email": "alice@example.com"},
+				"attendees": [{"name": "Bob Jones", "email": "bob@example.com"}, {"email": "carol@example.com"}]
+			},
+			"google_calendar_event": {"id": "evt-1", "summary": "Acme Kickoff", "recurringEventId": "series-1"}
+		}}}}}`)
+
+		state, err := ParseCache(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		doc := state.Documents["doc1"]
+		if doc.UpdatedAt != "2026-01-21T11:00:00Z" || doc.WorkspaceID != "ws-1" || doc.IsDeleted() {
+			t.Errorf("Unexpected metadata: %+v", doc)
+		}
+		if doc.People == nil || doc.People.Creator == nil || doc.People.Creator.Email != "alice@example.com" {
+			t.Errorf("Expected creator to be decoded, got %+v", doc.People)
+		}
+		if got := doc.Attendees(); len(got) != 2 || got[0].Name != "Bob Jones" || got[1].Email != "carol@example.com" {
+			t.Errorf("Unexpected attendees: %+v", got)
+		}
+		if doc.GoogleCalendarEvent == nil || doc.GoogleCalendarEvent.RecurringEventID != "series-1" {
+			t.Errorf("Expected calendar event to be decoded, got %+v", doc.GoogleCalendarEvent)
+		}
+	})
+
+	t.Run("assigns folders from document lists", func(t *testing.T) {
+		data := []byte(`{"cache": {"state": {
+			"documents": {"doc1": {"id": "doc1"}, "doc2": {"id": "doc2"}},
+			"sharedDocuments": {"doc3": {"id": "doc3"}},
+			"documentLists": {"list-b": ["doc1", "doc3"], "list-a": [{"document_id": "doc1"}], "list-c": [{"id": "doc2"}]},
+			"documentListsMetadata": {"list-a": {"id": "list-a", "title": "Customers"}, "list-b": {"id": "list-b", "title": "Acme"}}
+		}}}`)
+
+		state, err := ParseCache(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		tests := map[string]string{
+			"doc1": "Acme, Customers",
+			"doc2": "list-c",
+		}
+		for id, want := range tests {
+			if got := strings.Join(state.Documents[id].Folders, ", "); got != want {
+				t.Errorf("%s: expected folders %q, got %q", id, want, got)
+			}
+		}
+		if got := strings.Join(state.SharedDocuments["doc3"].Folders, ", "); got != "Acme" {
+			t.Errorf("doc3: expected folders %q, got %q", "Acme", got)
+		}
+	})
+}
+
+func TestLoadCacheNotes(t *testing.T) {
+	state, err := LoadCache(filepath.Join("testdata", "cache-panels.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("assigns panels that aren't deleted, oldest first", func(t *testing.T) {
+		var ids []string
+		for _, p := range state.Documents["doc-2"].Panels {
+			ids = append(ids, p.ID)
+		}
+		if got := strings.Join(ids, ","); got != "panel-a,panel-b" {
+			t.Errorf("Expected panels panel-a,panel-b, got %q", got)
+		}
+		if panels := state.Documents["doc-1"].Panels; len(panels) != 1 || panels[0].ID != "panel-1" {
+			t.Errorf("Expected the deleted panel to be left out, got %+v", panels)
+		}
+	})
+
+	t.Run("writes typed notes and panels as separate sections", func(t *testing.T) {
+		tests := []struct {
+			id   string
+			mine string
+			ai   string
+		}{
+			{"doc-1", "- Ask about volume discounts", "### Pricing\n\n- Customer wants a volume discount"},
+			{"doc-2", "Typed in the **editor**", "### Summary\n\nAgreed on the Q2 priorities\n\n### Action Items\n\nDraft the Q2 plan"},
+		}
+		for _, tt := range tests {
+			doc := state.Documents[tt.id]
+			content := FormatDocumentMarkdown(&doc, nil)
+
+			mine, ai := ExtractNotesFromMarkdown(content)
+			if mine != tt.mine {
+				t.Errorf("%s: expected my notes %q, got %q", tt.id, tt.mine, mine)
+			}
+			if ai != tt.ai {
+				t.Errorf("%s: expected AI notes %q, got %q", tt.id, tt.ai, ai)
+			}
+		}
+	})
+
+	t.Run("selects sections", func(t *testing.T) {
+		doc := state.Documents["doc-1"]
+
+		mine := FormatDocumentMarkdownWithOptions(&doc, nil, FormatOptions{Notes: NotesMine})
+		if !strings.Contains(mine, "## My Notes") || strings.Contains(mine, "## AI-Generated Notes") {
+			t.Errorf("Expected only my notes:\n%s", mine)
+		}
+		ai := FormatDocumentMarkdownWithOptions(&doc, nil, FormatOptions{Notes: NotesAI})
+		if strings.Contains(ai, "## My Notes") || !strings.Contains(ai, "volume discount") {
+			t.Errorf("Expected only AI notes:\n%s", ai)
+		}
+	})
+}
+
+func TestDocumentAttendees(t *testing.T) {
+	t.Run("merges calendar invitees and Granola attendees", func(t *testing.T) {
+		doc := &Document{
+			GoogleCalendarEvent: &CalendarEvent{
+				Organizer: &CalendarAttendee{Email: "alice@example.com"},
+				Attendees: []CalendarAttendee{
+					{Email: "Alice@example.com"},
+					{Email: "bob@example.com", DisplayName: "Bob Jones"},
+					{Email: "room-4@resource.example.com", DisplayName: "Room 4", Resource: true},
+				},
+			},
+			People: &People{Attendees: []Person{
+				{Name: "Alice Smith", Email: "alice@example.com"},
+				{Name: "Dana Lee"},
+			}},
+		}
+
+		want := []Attendee{
+			{Name: "Alice Smith", Email: "Alice@example.com", Organizer: true},
+			{Name: "Bob Jones", Email: "bob@example.com"},
+			{Name: "Dana Lee"},
+		}
+		got := doc.Attendees()
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("uses the explicit organizer flag", func(t *testing.T) {
+		doc := &Document{GoogleCalendarEvent: &CalendarEvent{Attendees: []CalendarAttendee{
+			{Email: "bob@example.com", Organizer: true},
+		}}}
+
+		if got := doc.Attendees(); len(got) != 1 || !got[0].Organizer {
+			t.Errorf("Expected organizer, got %+v", got)
+		}
+	})
+
+	t.Run("returns nothing without attendee information", func(t *testing.T) {
+		doc := &Document{}
+		if got := doc.Attendees(); len(got) != 0 {
+			t.Errorf("Expected no attendees, got %+v", got)
+		}
+	})
+}
+
+func TestConferenceURL(t *testing.T) {
+	event := &CalendarEvent{
+		HangoutLink: "https://meet.google.com/abc-defg-hij",
+		ConferenceData: &ConferenceData{EntryPoints: []ConferenceEntryPoint{
+			{EntryPointType: "phone", URI: "tel:+1-555-0100"},
+			{EntryPointType: "video", URI: "https://zoom.us/j/123"},
+		}},
+	}
+	if got := event.ConferenceURL(); got != "https://zoom.us/j/123" {
+		t.Errorf("Expected video entry point, got %q", got)
+	}
+
+	event.ConferenceData = nil
+	if got := event.ConferenceURL(); got != "https://meet.google.com/abc-defg-hij" {
+		t.Errorf("Expected hangout link, got %q", got)
+	}
+}
+
+func TestFindCacheFileIn(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cache-v3.json", "cache-v10.json", "cache-v6.json", "supabase.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, err := FindCacheFileIn(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if filepath.Base(path) != "cache-v10.json" {
+		t.Errorf("Expected cache-v10.json, got %s", path)
+	}
+
+	if _, err := FindCacheFileIn(t.TempDir()); err == nil {
+		t.Error("Expected error for directory without caches")
+	}
+}
+
+func TestIsCacheFile(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/path/to/cache-v6.json", true},
+		{"cache-v10.json", true},
+		{"/path/to/cache-v6.json.tmp", false},
+		{"/path/to/old-cache-v6.json", false},
+		{"/path/to/supabase.json", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsCacheFile(tt.path); got != tt.expected {
+				t.Errorf("IsCacheFile(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestExtractVersion(t *testing.T) {
 	tests := []struct {
 		path     string
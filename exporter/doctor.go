@@ -0,0 +1,250 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LatestTestedCacheVersion is the newest cache-vN.json version granary has
+// been tested against.
+const LatestTestedCacheVersion = 6
+
+// Cache formats reported by DiagnoseCache.
+const (
+	CacheFormatString = "string"
+	CacheFormatObject = "object"
+)
+
+// CacheDiagnosis describes the shape of a cache file.
+type CacheDiagnosis struct {
+	Path                    string   `json:"path"`
+	Version                 int      `json:"version"`
+	SizeBytes               int64    `json:"size_bytes"`
+	Format                  string   `json:"format"`
+	StateKeys               []string `json:"state_keys"`
+	Documents               int      `json:"documents"`
+	SharedDocuments         int      `json:"shared_documents"`
+	Deleted                 int      `json:"deleted"`
+	Transcripts             int      `json:"transcripts"`
+	TranscriptEntries       int      `json:"transcript_entries"`
+	Exportable              int      `json:"exportable"`
+	UnknownDocumentFields   []string `json:"unknown_document_fields"`
+	UnknownTranscriptFields []string `json:"unknown_transcript_fields"`
+	Warnings                []string `json:"warnings"`
+}
+
+// DiagnoseCache inspects the cache file at path. Unlike LoadCache it decodes
+// the whole state generically, so it uses more memory but sees every field.
+func DiagnoseCache(path string) (*CacheDiagnosis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	d, err := DiagnoseCacheData(data)
+	if err != nil {
+		return nil, err
+	}
+	d.Path = path
+	d.Version = CacheVersion(path)
+	d.SizeBytes = int64(len(data))
+
+	if d.Version > LatestTestedCacheVersion {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("cache-v%d is newer than the latest version granary has been tested with (v%d)", d.Version, LatestTestedCacheVersion))
+	}
+
+	return d, nil
+}
+
+// DiagnoseCacheData inspects raw cache JSON.
+func DiagnoseCacheData(data []byte) (*CacheDiagnosis, error) {
+	d := &CacheDiagnosis{
+		StateKeys:               []string{},
+		UnknownDocumentFields:   []string{},
+		UnknownTranscriptFields: []string{},
+		Warnings:                []string{},
+	}
+
+	var outer map[string]json.RawMessage
+	if err := json.Unmarshal(data, &outer); err != nil {
+		return nil, fmt.Errorf("failed to parse cache JSON: %w", err)
+	}
+
+	cache := outer["cache"]
+	if len(cache) == 0 {
+		return nil, errCacheFieldEmpty
+	}
+
+	d.Format = CacheFormatObject
+	if cache[0] == '"' {
+		d.Format = CacheFormatString
+		var cacheStr string
+		if err := json.Unmarshal(cache, &cacheStr); err != nil {
+			return nil, fmt.Errorf("failed to parse cache string: %w", err)
+		}
+		cache = json.RawMessage(cacheStr)
+	}
+
+	var inner struct {
+		State map[string]json.RawMessage `json:"state"`
+	}
+	if err := json.Unmarshal(cache, &inner); err != nil {
+		return nil, fmt.Errorf("failed to parse inner cache JSON: %w", err)
+	}
+	if inner.State == nil {
+		d.Warnings = append(d.Warnings, `cache has no "state" object`)
+		return d, nil
+	}
+
+	for key := range inner.State {
+		d.StateKeys = append(d.StateKeys, key)
+	}
+	sort.Strings(d.StateKeys)
+
+	for _, key := range []string{"documents", "transcripts"} {
+		if _, ok := inner.State[key]; !ok {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("state has no %q key", key))
+		}
+	}
+
+	documents, docFields, err := decodeDiagnosticMap[Document](inner.State["documents"], "documents")
+	if err != nil {
+		return nil, err
+	}
+	shared, sharedFields, err := decodeDiagnosticMap[Document](inner.State["sharedDocuments"], "sharedDocuments")
+	if err != nil {
+		return nil, err
+	}
+	transcripts, entryFields, err := decodeDiagnosticTranscripts(inner.State["transcripts"])
+	if err != nil {
+		return nil, err
+	}
+
+	d.Documents = len(documents)
+	d.SharedDocuments = len(shared)
+	d.Transcripts = len(transcripts)
+	for _, entries := range transcripts {
+		d.TranscriptEntries += len(entries)
+	}
+
+	for field := range sharedFields {
+		docFields[field] = true
+	}
+	d.UnknownDocumentFields = unknownFields(docFields, Document{})
+	d.UnknownTranscriptFields = unknownFields(entryFields, TranscriptEntry{})
+
+	// AI notes live in panels, which count towards exportable content
+	state := &CacheState{Documents: documents, SharedDocuments: shared, Transcripts: transcripts}
+	if raw := inner.State["documentPanels"]; len(raw) > 0 {
+		if err := json.Unmarshal(raw, &state.DocumentPanels); err != nil {
+			return nil, fmt.Errorf("unexpected shape for documentPanels: %w", err)
+		}
+	}
+	state.assignPanels()
+	for _, docs := range []map[string]Document{documents, shared} {
+		for _, doc := range docs {
+			if doc.IsDeleted() {
+				d.Deleted++
+			}
+		}
+	}
+	for _, doc := range state.AllDocuments() {
+		if doc.HasExportableContent(transcripts) {
+			d.Exportable++
+		}
+	}
+
+	if d.Documents+d.SharedDocuments > 0 {
+		if d.Exportable == 0 {
+			d.Warnings = append(d.Warnings, "no documents have notes or transcripts to export")
+		}
+		for _, field := range []string{"id", "title", "created_at"} {
+			if !docFields[field] {
+				d.Warnings = append(d.Warnings, fmt.Sprintf("no document has a %q field", field))
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// decodeDiagnosticMap decodes a map of objects and collects every field name seen.
+func decodeDiagnosticMap[T any](raw json.RawMessage, key string) (map[string]T, map[string]bool, error) {
+	fields := make(map[string]bool)
+	if len(raw) == 0 || string(raw) == "null" {
+		return map[string]T{}, fields, nil
+	}
+
+	var generic map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, nil, fmt.Errorf("unexpected shape for %s: %w", key, err)
+	}
+	for _, obj := range generic {
+		for field := range obj {
+			fields[field] = true
+		}
+	}
+
+	var typed map[string]T
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return nil, nil, fmt.Errorf("unexpected shape for %s: %w", key, err)
+	}
+	return typed, fields, nil
+}
+
+// decodeDiagnosticTranscripts decodes transcripts and collects every entry field name seen.
+func decodeDiagnosticTranscripts(raw json.RawMessage) (map[string][]TranscriptEntry, map[string]bool, error) {
+	fields := make(map[string]bool)
+	if len(raw) == 0 || string(raw) == "null" {
+		return map[string][]TranscriptEntry{}, fields, nil
+	}
+
+	var generic map[string][]map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, nil, fmt.Errorf("unexpected shape for transcripts: %w", err)
+	}
+	for _, entries := range generic {
+		for _, entry := range entries {
+			for field := range entry {
+				fields[field] = true
+			}
+		}
+	}
+
+	var typed map[string][]TranscriptEntry
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return nil, nil, fmt.Errorf("unexpected shape for transcripts: %w", err)
+	}
+	return typed, fields, nil
+}
+
+// unknownFields returns the sorted field names in seen that model doesn't decode.
+func unknownFields(seen map[string]bool, model any) []string {
+	known := jsonFieldNames(reflect.TypeOf(model))
+
+	unknown := []string{}
+	for field := range seen {
+		if !known[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// jsonFieldNames returns the JSON names of a struct's fields.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
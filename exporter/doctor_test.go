@@ -0,0 +1,133 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseCache(t *testing.T) {
+	fixtures := []struct {
+		file   string
+		format string
+		keys   []string
+	}{
+		{"cache-v3.json", CacheFormatString, []string{"documents", "transcripts"}},
+		{"cache-v5.json", CacheFormatString, []string{"documents", "transcripts"}},
+		{"cache-v6.json", CacheFormatObject, []string{"documentLists", "documents", "sharedDocuments", "transcripts"}},
+	}
+
+	for _, f := range fixtures {
+		t.Run("diagnoses "+f.file, func(t *testing.T) {
+			path := filepath.Join("testdata", f.file)
+
+			d, err := DiagnoseCache(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if d.Format != f.format {
+				t.Errorf("Expected format %q, got %q", f.format, d.Format)
+			}
+			if !slices.Equal(d.StateKeys, f.keys) {
+				t.Errorf("Expected state keys %v, got %v", f.keys, d.StateKeys)
+			}
+			if d.Documents != 2 || d.Transcripts != 1 || d.TranscriptEntries != 2 || d.Exportable != 2 {
+				t.Errorf("Unexpected counts: %+v", d)
+			}
+			if len(d.Warnings) != 0 {
+				t.Errorf("Expected no warnings, got %v", d.Warnings)
+			}
+
+			// The fixture must also load through the regular parser
+			state, err := LoadCache(path)
+			if err != nil {
+				t.Fatalf("LoadCache failed: %v", err)
+			}
+			if len(state.Documents) != d.Documents {
+				t.Errorf("LoadCache found %d documents, doctor found %d", len(state.Documents), d.Documents)
+			}
+		})
+	}
+
+	t.Run("reports fields granary does not decode", func(t *testing.T) {
+		d, err := DiagnoseCache(filepath.Join("testdata", "cache-v6.json"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !slices.Contains(d.UnknownDocumentFields, "type") {
+			t.Errorf("Expected 'type' among unknown fields, got %v", d.UnknownDocumentFields)
+		}
+		if slices.Contains(d.UnknownDocumentFields, "title") {
+			t.Errorf("Known field 'title' reported as unknown: %v", d.UnknownDocumentFields)
+		}
+		if len(d.UnknownTranscriptFields) != 0 {
+			t.Errorf("Expected no unknown transcript fields, got %v", d.UnknownTranscriptFields)
+		}
+	})
+
+	t.Run("warns about versions newer than tested", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join("testdata", "cache-v6.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "cache-v7.json")
+		writeTestFile(t, path, string(data))
+
+		d, err := DiagnoseCache(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if d.Version != 7 {
+			t.Errorf("Expected version 7, got %d", d.Version)
+		}
+		if len(d.Warnings) != 1 || !strings.Contains(d.Warnings[0], "newer") {
+			t.Errorf("Expected a newer-version warning, got %v", d.Warnings)
+		}
+	})
+
+	t.Run("warns about missing state keys", func(t *testing.T) {
+		d, err := DiagnoseCacheData([]byte(`{"cache": {"state": {"documentLists": {}}}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(d.Warnings) != 2 {
+			t.Errorf("Expected 2 warnings, got %v", d.Warnings)
+		}
+	})
+
+	t.Run("warns when no document has exportable content", func(t *testing.T) {
+		d, err := DiagnoseCacheData([]byte(`{"cache": {"state": {"documents": {"a": {"id": "a", "title": "A", "created_at": "x"}}, "transcripts": {}}}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(d.Warnings) != 1 || !strings.Contains(d.Warnings[0], "no documents") {
+			t.Errorf("Expected an exportable-content warning, got %v", d.Warnings)
+		}
+	})
+
+	t.Run("counts documents whose only content is a notes panel", func(t *testing.T) {
+		d, err := DiagnoseCacheData([]byte(`{"cache": {"state": {
+			"documents": {"a": {"id": "a", "title": "A", "created_at": "x"}},
+			"documentPanels": {"a": {"p": {"id": "p", "document_id": "a", "title": "Summary",
+				"content": {"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Agreed on the Q2 priorities"}]}]}}}},
+			"transcripts": {}}}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if d.Exportable != 1 || len(d.Warnings) != 0 {
+			t.Errorf("Expected the document to be exportable, got %d exportable and warnings %v", d.Exportable, d.Warnings)
+		}
+	})
+
+	t.Run("fails on unexpected shape", func(t *testing.T) {
+		if _, err := DiagnoseCacheData([]byte(`{"cache": {"state": {"documents": []}}}`)); err == nil {
+			t.Error("Expected error for documents array")
+		}
+		if _, err := DiagnoseCacheData([]byte(`{"other": 1}`)); err == nil {
+			t.Error("Expected error for missing cache field")
+		}
+	})
+}
@@ -1,12 +1,154 @@
 package exporter
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Document represents a meeting document from the Granola cache.
 type Document struct {
-	ID            string `json:"id"`
-	Title         string `json:"title"`
-	CreatedAt     string `json:"created_at"`
-	NotesMarkdown string `json:"notes_markdown"`
-	NotesPlain    string `json:"notes_plain"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+	DeletedAt   string `json:"deleted_at,omitempty"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	// NotesMarkdown, NotesPlain and Notes are the notes the user typed, as
+	// markdown, plain text and Granola's structured editor content.
+	NotesMarkdown       string          `json:"notes_markdown"`
+	NotesPlain          string          `json:"notes_plain"`
+	Notes               json.RawMessage `json:"notes,omitempty"`
+	People              *People         `json:"people,omitempty"`
+	GoogleCalendarEvent *CalendarEvent  `json:"google_calendar_event,omitempty"`
+
+	// Folders holds the titles of the Granola folders (document lists) the
+	// document belongs to. It is filled in from the cache state, not decoded
+	// from the document itself.
+	Folders []string `json:"-"`
+	// Panels holds the document's AI-generated notes, oldest first. Like
+	// Folders, it is filled in from the cache state.
+	Panels []DocumentPanel `json:"-"`
+}
+
+// DocumentPanel is a panel of AI-generated notes Granola shows next to a
+// document, such as its summary.
+type DocumentPanel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Content is the panel as structured editor content, or as a string in
+	// older caches.
+	Content   json.RawMessage `json:"content,omitempty"`
+	CreatedAt string          `json:"created_at,omitempty"`
+	DeletedAt string          `json:"deleted_at,omitempty"`
+}
+
+// Markdown returns the panel's content as markdown.
+func (p DocumentPanel) Markdown() string {
+	if len(p.Content) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(p.Content, &text); err == nil {
+		return text
+	}
+	md, err := ProseMirrorToMarkdown(p.Content)
+	if err != nil {
+		return ""
+	}
+	return md
+}
+
+// People lists who created and attended a meeting.
+type People struct {
+	Creator   *Person  `json:"creator,omitempty"`
+	Attendees []Person `json:"attendees,omitempty"`
+}
+
+// Person is a meeting creator or attendee.
+type Person struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// CalendarEvent is the calendar event a meeting was recorded for.
+type CalendarEvent struct {
+	ID               string             `json:"id,omitempty"`
+	Summary          string             `json:"summary,omitempty"`
+	RecurringEventID string             `json:"recurringEventId,omitempty"`
+	Start            *CalendarTime      `json:"start,omitempty"`
+	End              *CalendarTime      `json:"end,omitempty"`
+	Organizer        *CalendarAttendee  `json:"organizer,omitempty"`
+	Attendees        []CalendarAttendee `json:"attendees,omitempty"`
+	HangoutLink      string             `json:"hangoutLink,omitempty"`
+	ConferenceData   *ConferenceData    `json:"conferenceData,omitempty"`
+}
+
+// CalendarTime is the start or end of a calendar event. All-day events only
+// have a Date.
+type CalendarTime struct {
+	DateTime string `json:"dateTime,omitempty"`
+	Date     string `json:"date,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// CalendarAttendee is a person invited to a calendar event.
+type CalendarAttendee struct {
+	Email       string `json:"email,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Organizer   bool   `json:"organizer,omitempty"`
+	Resource    bool   `json:"resource,omitempty"`
+}
+
+// ConferenceData holds the ways to join a calendar event's call.
+type ConferenceData struct {
+	EntryPoints []ConferenceEntryPoint `json:"entryPoints,omitempty"`
+}
+
+// ConferenceEntryPoint is one way to join a call, such as a video link or
+// a dial-in number.
+type ConferenceEntryPoint struct {
+	EntryPointType string `json:"entryPointType,omitempty"`
+	URI            string `json:"uri,omitempty"`
+}
+
+// ConferenceURL returns the video call link for the event, if any.
+func (e *CalendarEvent) ConferenceURL() string {
+	if e.ConferenceData != nil {
+		for _, ep := range e.ConferenceData.EntryPoints {
+			if ep.EntryPointType == "video" && ep.URI != "" {
+				return ep.URI
+			}
+		}
+	}
+	return e.HangoutLink
+}
+
+// Attendee is a meeting participant, merged from the calendar invite and
+// Granola's own attendee list.
+type Attendee struct {
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Organizer bool   `json:"organizer,omitempty"`
+}
+
+// String formats the attendee as "Name <email>", with whichever parts are known.
+func (a Attendee) String() string {
+	switch {
+	case a.Name != "" && a.Email != "":
+		return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+	case a.Name != "":
+		return a.Name
+	default:
+		return a.Email
+	}
+}
+
+// DocumentList is the metadata of a Granola folder.
+type DocumentList struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
 }
 
 // TranscriptEntry represents a single transcript entry from the Granola cache.
@@ -25,10 +167,19 @@ type CacheState struct {
 	Documents       map[string]Document          `json:"documents"`
 	SharedDocuments map[string]Document          `json:"sharedDocuments"`
 	Transcripts     map[string][]TranscriptEntry `json:"transcripts"`
+
+	// DocumentLists maps folder IDs to the IDs of the documents they contain,
+	// and DocumentListsMetadata maps folder IDs to their titles.
+	DocumentLists         map[string]documentListMembers `json:"documentLists"`
+	DocumentListsMetadata map[string]DocumentList        `json:"documentListsMetadata"`
+
+	// DocumentPanels maps document IDs to their AI-generated notes panels.
+	DocumentPanels documentPanels `json:"documentPanels"`
 }
 
 // AllDocuments returns all documents (owned + shared) as a single map.
 // Owned documents take precedence if a document ID appears in both.
+// Deleted documents are left out.
 func (s *CacheState) AllDocuments() map[string]Document {
 	all := make(map[string]Document, len(s.Documents)+len(s.SharedDocuments))
 	for id, doc := range s.SharedDocuments {
@@ -37,9 +188,180 @@ func (s *CacheState) AllDocuments() map[string]Document {
 	for id, doc := range s.Documents {
 		all[id] = doc
 	}
+	for id, doc := range all {
+		if doc.IsDeleted() {
+			delete(all, id)
+		}
+	}
 	return all
 }
 
+// assignFolders fills in each document's Folders from the document lists.
+func (s *CacheState) assignFolders() {
+	folders := make(map[string][]string)
+	for listID, members := range s.DocumentLists {
+		title := listID
+		if meta, ok := s.DocumentListsMetadata[listID]; ok && meta.Title != "" {
+			title = meta.Title
+		}
+		for _, docID := range members {
+			folders[docID] = append(folders[docID], title)
+		}
+	}
+
+	for _, docs := range []map[string]Document{s.Documents, s.SharedDocuments} {
+		for id, doc := range docs {
+			if f, ok := folders[id]; ok {
+				sort.Strings(f)
+				doc.Folders = f
+				docs[id] = doc
+			}
+		}
+	}
+}
+
+// assignPanels fills in each document's Panels from the document panels,
+// leaving out deleted panels.
+func (s *CacheState) assignPanels() {
+	for _, docs := range []map[string]Document{s.Documents, s.SharedDocuments} {
+		for id, doc := range docs {
+			var panels []DocumentPanel
+			for _, p := range s.DocumentPanels[id] {
+				if p.DeletedAt == "" {
+					panels = append(panels, p)
+				}
+			}
+			if len(panels) > 0 {
+				doc.Panels = panels
+				docs[id] = doc
+			}
+		}
+	}
+}
+
+// documentPanels maps document IDs to their panels, sorted by creation time.
+// In the cache each document's panels are an object keyed by panel ID;
+// panels that can't be decoded are skipped rather than failing the cache.
+type documentPanels map[string][]DocumentPanel
+
+func (p *documentPanels) UnmarshalJSON(data []byte) error {
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	panels := make(documentPanels, len(raw))
+	for docID, entries := range raw {
+		for panelID, entry := range entries {
+			var panel DocumentPanel
+			if err := json.Unmarshal(entry, &panel); err != nil {
+				continue
+			}
+			if panel.ID == "" {
+				panel.ID = panelID
+			}
+			panels[docID] = append(panels[docID], panel)
+		}
+		sort.Slice(panels[docID], func(i, j int) bool {
+			a, b := panels[docID][i], panels[docID][j]
+			if a.CreatedAt != b.CreatedAt {
+				return a.CreatedAt < b.CreatedAt
+			}
+			return a.ID < b.ID
+		})
+	}
+	*p = panels
+	return nil
+}
+
+// documentListMembers is the list of document IDs in a folder. Entries are
+// either bare IDs or objects carrying the ID.
+type documentListMembers []string
+
+func (m *documentListMembers) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(raw))
+	for _, entry := range raw {
+		var id string
+		if err := json.Unmarshal(entry, &id); err == nil {
+			ids = append(ids, id)
+			continue
+		}
+		var obj struct {
+			ID         string `json:"id"`
+			DocumentID string `json:"document_id"`
+		}
+		if err := json.Unmarshal(entry, &obj); err != nil {
+			return err
+		}
+		if obj.DocumentID != "" {
+			ids = append(ids, obj.DocumentID)
+		} else if obj.ID != "" {
+			ids = append(ids, obj.ID)
+		}
+	}
+	*m = ids
+	return nil
+}
+
+// IsDeleted reports whether the document was deleted in Granola.
+func (d *Document) IsDeleted() bool {
+	return d.DeletedAt != ""
+}
+
+// Attendees returns the meeting's attendees. Calendar invitees come first in
+// invite order, followed by anyone Granola lists who wasn't invited. Meeting
+// rooms and other calendar resources are left out.
+func (d *Document) Attendees() []Attendee {
+	var attendees []Attendee
+	seen := make(map[string]int)
+	add := func(a Attendee) {
+		key := strings.ToLower(a.Email)
+		if key == "" {
+			key = "name:" + a.Name
+		}
+		if key == "name:" {
+			return
+		}
+		if i, ok := seen[key]; ok {
+			if attendees[i].Name == "" {
+				attendees[i].Name = a.Name
+			}
+			attendees[i].Organizer = attendees[i].Organizer || a.Organizer
+			return
+		}
+		seen[key] = len(attendees)
+		attendees = append(attendees, a)
+	}
+
+	if event := d.GoogleCalendarEvent; event != nil {
+		var organizer string
+		if event.Organizer != nil {
+			organizer = strings.ToLower(event.Organizer.Email)
+		}
+		for _, a := range event.Attendees {
+			if a.Resource {
+				continue
+			}
+			add(Attendee{
+				Name:      a.DisplayName,
+				Email:     a.Email,
+				Organizer: a.Organizer || (organizer != "" && strings.ToLower(a.Email) == organizer),
+			})
+		}
+	}
+	if d.People != nil {
+		for _, p := range d.People.Attendees {
+			add(Attendee{Name: p.Name, Email: p.Email})
+		}
+	}
+	return attendees
+}
+
 // HasExportableContent returns true if the document has content worth exporting.
 // A document is exportable if it has a transcript OR notes with more than 10 characters.
 func (d *Document) HasExportableContent(transcripts map[string][]TranscriptEntry) bool {
@@ -52,14 +374,54 @@ func (d *Document) HasExportableContent(transcripts map[string][]TranscriptEntry
 	if len(d.NotesPlain) > 10 {
 		return true
 	}
+	if len(d.GetNotes()) > 10 {
+		return true
+	}
+	if len(d.GetAINotes()) > 10 {
+		return true
+	}
 	return false
 }
 
-// GetNotes returns the best available notes content.
-// Prefers notes_markdown, falls back to notes_plain.
+// GetNotes returns the notes the user typed during the meeting.
+// Prefers notes_markdown, falls back to notes_plain, then to the structured
+// notes converted to markdown.
 func (d *Document) GetNotes() string {
 	if d.NotesMarkdown != "" {
 		return d.NotesMarkdown
 	}
-	return d.NotesPlain
+	if d.NotesPlain != "" {
+		return d.NotesPlain
+	}
+	if len(d.Notes) > 0 {
+		if md, err := ProseMirrorToMarkdown(d.Notes); err == nil {
+			return md
+		}
+	}
+	return ""
+}
+
+// GetAINotes returns the AI-generated notes from the document's panels. When
+// there are several, each is put under a heading with the panel's title.
+func (d *Document) GetAINotes() string {
+	type section struct{ title, body string }
+	var sections []section
+	for _, p := range d.Panels {
+		if body := strings.TrimSpace(p.Markdown()); body != "" {
+			sections = append(sections, section{p.Title, body})
+		}
+	}
+
+	if len(sections) == 1 {
+		return sections[0].body
+	}
+	var parts []string
+	for _, s := range sections {
+		title := s.title
+		if title == "" {
+			title = "Notes"
+		}
+		parts = append(parts, "### "+title+"\n\n"+s.body)
+	}
+	return strings.Join(parts, "\n\n")
 }
@@ -2,29 +2,98 @@ package exporter
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // ExportResult holds statistics about an export operation.
 type ExportResult struct {
-	Written int
-	Skipped int
-	Empty   int
-	Errors  []ExportError
+	Written   int
+	Skipped   int
+	Empty     int
+	Errors    []ExportError
+	Documents []DocumentResult
+	// IndexErrors are failures to update the index pages. The documents
+	// were still exported.
+	IndexErrors []string
+	Duration    time.Duration
+	// CacheUnchanged is set when the export was skipped because the cache
+	// has not changed since the last run.
+	CacheUnchanged bool
 }
 
 // ExportError represents an error that occurred during export.
 type ExportError struct {
-	DocumentID string
-	Title      string
-	Error      string
+	DocumentID string `json:"id"`
+	Title      string `json:"title"`
+	Error      string `json:"error"`
+}
+
+// DocumentAction describes what happened to a single document during export.
+type DocumentAction string
+
+const (
+	ActionWritten DocumentAction = "written"
+	ActionSkipped DocumentAction = "skipped"
+	ActionEmpty   DocumentAction = "empty"
+	ActionError   DocumentAction = "error"
+)
+
+// DocumentResult records the outcome of exporting a single document.
+type DocumentResult struct {
+	DocumentID    string           `json:"id"`
+	Title         string           `json:"title"`
+	CreatedAt     string           `json:"created_at,omitempty"`
+	UpdatedAt     string           `json:"updated_at,omitempty"`
+	WorkspaceID   string           `json:"workspace_id,omitempty"`
+	Attendees     []Attendee       `json:"attendees,omitempty"`
+	CalendarEvent string           `json:"calendar_event,omitempty"`
+	Scheduled     string           `json:"scheduled,omitempty"`
+	ConferenceURL string           `json:"conference_url,omitempty"`
+	Stats         *TranscriptStats `json:"stats,omitempty"`
+	Folders       []string         `json:"folders,omitempty"`
+	Filename      string           `json:"filename,omitempty"`
+	Action        DocumentAction   `json:"action"`
+	Error         string           `json:"error,omitempty"`
 }
 
 // Exporter handles exporting Granola documents to markdown files.
 type Exporter struct {
 	OutputDir string
+	// Logger receives progress and error messages. A nil Logger discards them.
+	Logger *slog.Logger
+	// Jobs is the number of documents exported concurrently.
+	// Zero or less uses one worker per CPU.
+	Jobs int
+	// Version identifies the granary build. Per-document state recorded by
+	// a different version is ignored so formatting changes are picked up.
+	Version string
+	// State is the export state to read and update. When nil it is loaded
+	// from OutputDir. Export saves it after every run.
+	State *ExportState
+	// Format controls how documents are rendered.
+	Format FormatOptions
+	// MirrorFolders writes each document into a directory named after its
+	// Granola folder. Documents in several folders are written to the first
+	// one alphabetically and symlinked from the others.
+	MirrorFolders bool
+	// SeriesIndex writes an index page for each recurring meeting series
+	// to SeriesDirName, linking its meetings in date order.
+	SeriesIndex bool
+	// Index writes IndexFilename, a table of every exported meeting.
+	Index bool
+	// ByMonth writes each document into a directory named after the month
+	// it was created, such as 2026-01. With MirrorFolders the month
+	// directories are inside each folder's directory. The index, when
+	// written, gets a page per month.
+	ByMonth bool
 }
 
 // NewExporter creates a new Exporter with the given output directory.
@@ -39,12 +108,15 @@ func DefaultOutputDir() string {
 }
 
 // Export exports all exportable documents from the cache state.
-func (e *Exporter) Export(state *CacheState, verbose bool) (*ExportResult, error) {
+// Documents are processed by up to Jobs workers; results and log records are
+// still reported in filename order so output is deterministic.
+func (e *Exporter) Export(state *CacheState) (*ExportResult, error) {
 	// Ensure output directory exists
 	if err := os.MkdirAll(e.OutputDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	start := time.Now()
 	result := &ExportResult{}
 
 	// Collect exportable documents (owned + shared)
@@ -55,37 +127,142 @@ func (e *Exporter) Export(state *CacheState, verbose bool) (*ExportResult, error
 		}
 	}
 
-	if verbose {
-		fmt.Printf("Found %d documents with content to export\n\n", len(exportable))
-		fmt.Println("Exporting Granola documents:")
-		fmt.Println(strings.Repeat("=", 70))
-	}
+	logger := e.logger()
+	logger.Info("found documents with content to export", "count", len(exportable))
 
 	// Build filename map: assign unique filenames using document ID for collisions
 	filenameMap := buildFilenameMap(exportable)
+	if e.ByMonth {
+		for _, doc := range exportable {
+			filenameMap[doc.ID] = filepath.Join(monthDir(doc.CreatedAt), filenameMap[doc.ID])
+		}
+	}
+	links := make(map[string][]string)
+	if e.MirrorFolders {
+		for _, doc := range exportable {
+			filenameMap[doc.ID], links[doc.ID] = folderPaths(&doc, filenameMap[doc.ID])
+		}
+	}
 
-	// Export each document
-	for _, doc := range exportable {
-		err := e.exportDocument(&doc, state.Transcripts, filenameMap, result, verbose)
+	sort.Slice(exportable, func(i, j int) bool {
+		return filenameMap[exportable[i].ID] < filenameMap[exportable[j].ID]
+	})
+
+	exportState := e.State
+	if exportState == nil {
+		loaded, err := LoadExportState(e.OutputDir)
 		if err != nil {
-			result.Errors = append(result.Errors, ExportError{
-				DocumentID: doc.ID,
-				Title:      doc.Title,
-				Error:      err.Error(),
-			})
-			if verbose {
-				fmt.Printf("✗ Error with %s (%s): %s\n", doc.ID, doc.Title, err.Error())
+			return nil, err
+		}
+		exportState = loaded
+	}
+
+	// Per-document state is only trusted to skip work if it was written by
+	// this version, but file locations are always used to find moved files
+	trusted := exportState.Version == e.Version
+	previous := make(map[string]*DocumentState, len(exportState.Documents))
+	for id, ds := range exportState.Documents {
+		previous[id] = &ds
+	}
+	// The cache fingerprint only vouches for a run with the options it was
+	// recorded with. Callers record it again once a run fully succeeded, so
+	// a failed run is retried even when the cache doesn't change
+	exportState.Cache = nil
+	exportState.Version = e.Version
+	exportState.Format = e.Format
+	exportState.Folders = e.MirrorFolders
+	exportState.SeriesIndex = e.SeriesIndex
+	exportState.Index = e.Index
+	exportState.ByMonth = e.ByMonth
+	exportState.Documents = make(map[string]DocumentState, len(exportable))
+
+	// Moves happen up front: renaming while other documents are written
+	// could move or replace a file another worker just wrote
+	moved, moveErrors := e.relocateAll(exportable, filenameMap, previous)
+
+	// Workers fill in outcomes by index; only this goroutine touches result
+	outcomes := make([]documentOutcome, len(exportable))
+	done := make(chan int)
+	indexes := make(chan int)
+
+	jobs := min(e.jobs(), max(len(exportable), 1))
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				doc := &exportable[i]
+				outcomes[i] = e.exportDocument(exportJob{
+					doc:       doc,
+					filename:  filenameMap[doc.ID],
+					links:     links[doc.ID],
+					previous:  previous[doc.ID],
+					trusted:   trusted,
+					movedFrom: moved[doc.ID],
+					moveErr:   moveErrors[doc.ID],
+				}, state.Transcripts)
+				done <- i
 			}
+		}()
+	}
+
+	go func() {
+		for i := range exportable {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(done)
+	}()
+
+	// Report outcomes in order as soon as every earlier document has finished
+	finished := make([]bool, len(exportable))
+	next := 0
+	for i := range done {
+		finished[i] = true
+		for next < len(exportable) && finished[next] {
+			result.add(&exportable[next], outcomes[next], logger)
+			if outcomes[next].state != nil {
+				exportState.Documents[exportable[next].ID] = *outcomes[next].state
+			} else if prev, ok := previous[exportable[next].ID]; ok && outcomes[next].action == ActionError {
+				// Keep the old entry so a transient failure doesn't lose track of the file
+				exportState.Documents[exportable[next].ID] = *prev
+			}
+			next++
+		}
+	}
+
+	for _, outcome := range outcomes {
+		for _, dir := range outcome.vacated {
+			removeEmptyDirs(e.OutputDir, dir)
 		}
 	}
 
-	if verbose {
-		fmt.Printf("\n%s\n", strings.Repeat("=", 70))
+	if err := exportState.Save(e.OutputDir); err != nil {
+		return nil, err
+	}
+
+	if err := e.writeSeriesIndexes(seriesMeetings(exportable, outcomes)); err != nil {
+		result.addIndexError(fmt.Errorf("failed to update series indexes: %w", err), logger)
+	}
+	if err := e.writeIndex(exportState, exportable); err != nil {
+		result.addIndexError(fmt.Errorf("failed to update %s: %w", IndexFilename, err), logger)
 	}
 
+	result.Duration = time.Since(start)
+
 	return result, nil
 }
 
+// jobs returns the number of export workers to run.
+func (e *Exporter) jobs() int {
+	if e.Jobs <= 0 {
+		return runtime.NumCPU()
+	}
+	return e.Jobs
+}
+
 // buildFilenameMap assigns a stable unique filename to each document.
 // Documents with unique title+date get the normal filename.
 // Documents that collide get a short ID suffix appended.
@@ -127,82 +304,269 @@ func buildFilenameMap(docs []Document) map[string]string {
 	return result
 }
 
-func (e *Exporter) exportDocument(doc *Document, transcripts map[string][]TranscriptEntry, filenameMap map[string]string, result *ExportResult, verbose bool) error {
-	// Get transcript if available
-	transcript := transcripts[doc.ID]
+// monthDir returns the directory a document created at timestamp is
+// written to when exporting by month.
+func monthDir(timestamp string) string {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
+		return "unknown-date"
+	}
+	return t.Format("2006-01")
+}
+
+// logger returns the configured logger, or one that discards everything.
+func (e *Exporter) logger() *slog.Logger {
+	if e.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return e.Logger
+}
 
-	// Check if both notes and transcript are empty
-	notes := doc.GetNotes()
-	if (notes == "" || strings.TrimSpace(notes) == "") && len(transcript) == 0 {
-		result.Empty++
-		return nil
+// documentOutcome is what exporting a single document produced.
+type documentOutcome struct {
+	action   DocumentAction
+	filename string
+	err      error
+	// state is recorded for the next run; nil when the document has no file
+	state *DocumentState
+	// movedFrom is the previous filename when the file was moved
+	movedFrom string
+	// cleanup counts transcript entries removed before rendering
+	cleanup TranscriptCleanup
+	// stats describe the transcript in the file, if it has one
+	stats *TranscriptStats
+	// vacated are directories files or links were removed from, which may
+	// now be empty
+	vacated []string
+
+	// Details for the log record of a written document
+	content string
+	words   int
+	bytes   int
+}
+
+// exportJob is a document to export and where to put it.
+type exportJob struct {
+	doc      *Document
+	filename string
+	// links are the paths to symlink to filename from other folders
+	links []string
+	// previous is the state recorded for the document on the last run
+	previous *DocumentState
+	// trusted reports whether previous can be used to skip unchanged files
+	trusted bool
+	// movedFrom is the previous filename if the file was moved before the
+	// workers started, and moveErr the error if moving it failed
+	movedFrom string
+	moveErr   error
+}
+
+func (e *Exporter) exportDocument(job exportJob, transcripts map[string][]TranscriptEntry) documentOutcome {
+	if job.moveErr != nil {
+		return documentOutcome{action: ActionError, filename: job.filename, err: job.moveErr}
+	}
+	movedFrom := job.movedFrom
+
+	previous := job.previous
+	if !job.trusted {
+		previous = nil
+	}
+	outcome := e.writeDocument(job.doc, transcripts, job.filename, previous)
+	if movedFrom != "" {
+		outcome.movedFrom = movedFrom
+		outcome.vacated = append(outcome.vacated, filepath.Dir(filepath.Join(e.OutputDir, movedFrom)))
+	}
+	if outcome.state == nil {
+		return outcome
+	}
+
+	var staleLinks []string
+	if job.previous != nil {
+		staleLinks = job.previous.Links
+	}
+	vacated, err := e.syncLinks(job.filename, job.links, staleLinks)
+	if err != nil {
+		return documentOutcome{action: ActionError, filename: job.filename, err: err, vacated: outcome.vacated}
+	}
+	outcome.vacated = append(outcome.vacated, vacated...)
+	state := *outcome.state
+	state.Links = job.links
+	outcome.state = &state
+	return outcome
+}
+
+func (e *Exporter) writeDocument(doc *Document, transcripts map[string][]TranscriptEntry, filename string, previous *DocumentState) documentOutcome {
+	// Get transcript if available, without interim and repeated entries
+	transcript, cleanup := CleanTranscript(transcripts[doc.ID])
+
+	// Check if both the selected notes and transcript are empty
+	mine, ai := e.Format.notes(doc)
+	if mine == "" && ai == "" && len(transcript) == 0 {
+		return documentOutcome{action: ActionEmpty}
 	}
 
-	filename := filenameMap[doc.ID]
 	outputPath := filepath.Join(e.OutputDir, filename)
 
-	// If file exists and cache has no transcript, try to preserve transcript from file
-	if _, err := os.Stat(outputPath); err == nil && len(transcript) == 0 {
-		existingContent, err := os.ReadFile(outputPath)
-		if err == nil && strings.Contains(string(existingContent), "## Transcript") {
-			transcript = ExtractTranscriptFromMarkdown(string(existingContent))
-		}
+	// Same inputs as last time and the file hasn't been touched since: nothing to do
+	inputHash := documentInputHash(doc, transcript, e.Format)
+	if previous.matches(filename, inputHash, outputPath) {
+		return documentOutcome{action: ActionSkipped, filename: filename, state: previous, stats: previous.Stats}
+	}
+
+	existingContent, readErr := os.ReadFile(outputPath)
+
+	// If file exists and cache has no transcript, try to preserve transcript
+	// from file
+	if readErr == nil && len(transcript) == 0 {
+		transcript = keptTranscript(doc, string(existingContent))
 	}
 
 	// Format content with latest notes and best available transcript
-	content := FormatDocumentMarkdown(doc, transcript)
+	content := FormatDocumentMarkdownWithOptions(doc, transcript, e.Format)
+	stats := ComputeTranscriptStats(transcript)
 
 	// Check if file exists and content is identical
-	if existingContent, err := os.ReadFile(outputPath); err == nil {
-		if string(existingContent) == content {
-			result.Skipped++
-			return nil
-		}
+	if readErr == nil && string(existingContent) == content {
+		return documentOutcome{action: ActionSkipped, filename: filename, state: newDocumentState(filename, inputHash, outputPath, content, len(transcript) > 0, stats), stats: stats}
 	}
 
 	// Write the file
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return documentOutcome{action: ActionError, filename: filename, err: fmt.Errorf("failed to create folder: %w", err)}
+	}
 	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+		return documentOutcome{action: ActionError, filename: filename, err: fmt.Errorf("failed to write file: %w", err)}
+	}
+
+	// Describe what was included
+	var contentParts []string
+	if mine != "" {
+		contentParts = append(contentParts, "my notes")
+	}
+	if ai != "" {
+		contentParts = append(contentParts, "notes")
+	}
+	if len(transcript) > 0 {
+		contentParts = append(contentParts, fmt.Sprintf("transcript (%d entries)", len(transcript)))
 	}
 
-	if verbose {
-		// Count words
-		wordCount := len(strings.Fields(content))
+	return documentOutcome{
+		action:   ActionWritten,
+		filename: filename,
+		content:  strings.Join(contentParts, " + "),
+		words:    len(strings.Fields(content)),
+		bytes:    len(content),
+		state:    newDocumentState(filename, inputHash, outputPath, content, len(transcript) > 0, stats),
+		cleanup:  cleanup,
+		stats:    stats,
+	}
+}
+
+// keptTranscript returns the transcript in an existing file, as long as the
+// file was written for doc.
+func keptTranscript(doc *Document, content string) []TranscriptEntry {
+	if !strings.Contains(content, "## Transcript") || ExtractHeaderFromMarkdown(content).MeetingID != doc.ID {
+		return nil
+	}
+	return ExtractTranscriptFromMarkdown(content)
+}
 
-		// Get file size
-		info, _ := os.Stat(outputPath)
-		fileSize := info.Size()
+// add records a document outcome in the result and logs it.
+func (r *ExportResult) add(doc *Document, outcome documentOutcome, logger *slog.Logger) {
+	docResult := DocumentResult{
+		DocumentID:  doc.ID,
+		Title:       doc.Title,
+		CreatedAt:   doc.CreatedAt,
+		UpdatedAt:   doc.UpdatedAt,
+		WorkspaceID: doc.WorkspaceID,
+		Attendees:   doc.Attendees(),
+		Folders:     doc.Folders,
+		Filename:    outcome.filename,
+		Action:      outcome.action,
+		Stats:       outcome.stats,
+	}
+	if event := doc.GoogleCalendarEvent; event != nil {
+		docResult.CalendarEvent = event.Summary
+		docResult.Scheduled = FormatSchedule(event.Start, event.End)
+		docResult.ConferenceURL = event.ConferenceURL()
+	}
 
-		// Describe what was included
-		var contentParts []string
-		if notes != "" && strings.TrimSpace(notes) != "" {
-			contentParts = append(contentParts, "notes")
-		}
-		if len(transcript) > 0 {
-			contentParts = append(contentParts, fmt.Sprintf("transcript (%d entries)", len(transcript)))
-		}
+	if outcome.movedFrom != "" {
+		logger.Info("moved document", "from", outcome.movedFrom, "to", outcome.filename)
+	}
 
-		fmt.Printf("✓ %s\n", filename)
-		fmt.Printf("  [%s] %s words, %s bytes\n", strings.Join(contentParts, " + "), NumberWithCommas(wordCount), NumberWithCommas(int(fileSize)))
+	switch outcome.action {
+	case ActionWritten:
+		r.Written++
+		logger.Info("wrote document",
+			"file", outcome.filename,
+			"content", outcome.content,
+			"words", outcome.words,
+			"bytes", outcome.bytes,
+		)
+		if outcome.cleanup.Removed() > 0 {
+			logger.Debug("removed transcript entries",
+				"file", outcome.filename,
+				"interim", outcome.cleanup.Interim,
+				"duplicates", outcome.cleanup.Duplicates,
+			)
+		}
+	case ActionSkipped:
+		r.Skipped++
+		logger.Debug("skipped unchanged document", "file", outcome.filename)
+	case ActionEmpty:
+		r.Empty++
+		logger.Debug("skipped empty document", "id", doc.ID, "title", doc.Title)
+	case ActionError:
+		docResult.Error = outcome.err.Error()
+		r.Errors = append(r.Errors, ExportError{
+			DocumentID: doc.ID,
+			Title:      doc.Title,
+			Error:      outcome.err.Error(),
+		})
+		logger.Error("failed to export document", "id", doc.ID, "title", doc.Title, "error", outcome.err)
 	}
 
-	result.Written++
-	return nil
+	r.Documents = append(r.Documents, docResult)
 }
 
 // PrintSummary prints a summary of the export result.
 func (r *ExportResult) PrintSummary(outputDir string) {
-	fmt.Println("\nSummary:")
-	fmt.Printf("  Written: %d documents\n", r.Written)
-	fmt.Printf("  Skipped (unchanged): %d documents\n", r.Skipped)
-	fmt.Printf("  Empty: %d documents\n", r.Empty)
-	fmt.Printf("  Errors: %d\n", len(r.Errors))
-	fmt.Printf("\nAll documents saved to: %s\n", outputDir)
+	r.WriteSummary(os.Stdout, outputDir)
+}
+
+// addIndexError records a failure to update the index pages and logs it.
+func (r *ExportResult) addIndexError(err error, logger *slog.Logger) {
+	r.IndexErrors = append(r.IndexErrors, err.Error())
+	logger.Error("failed to update index pages", "error", err)
+}
+
+// WriteSummary writes a human-readable summary of the export result to w.
+func (r *ExportResult) WriteSummary(w io.Writer, outputDir string) {
+	if r.CacheUnchanged {
+		fmt.Fprintln(w, "\nSummary:")
+		fmt.Fprintln(w, "  Cache unchanged since last run, nothing to export")
+		fmt.Fprintf(w, "\nAll documents saved to: %s\n", outputDir)
+		return
+	}
+
+	fmt.Fprintln(w, "\nSummary:")
+	fmt.Fprintf(w, "  Written: %d documents\n", r.Written)
+	fmt.Fprintf(w, "  Skipped (unchanged): %d documents\n", r.Skipped)
+	fmt.Fprintf(w, "  Empty: %d documents\n", r.Empty)
+	fmt.Fprintf(w, "  Errors: %d\n", len(r.Errors))
+	fmt.Fprintf(w, "\nAll documents saved to: %s\n", outputDir)
 
 	if len(r.Errors) > 0 {
-		fmt.Println("\nErrors:")
+		fmt.Fprintln(w, "\nErrors:")
 		for _, e := range r.Errors {
-			fmt.Printf("  %s: %s\n", e.DocumentID, e.Error)
+			fmt.Fprintf(w, "  %s: %s\n", e.DocumentID, e.Error)
+		}
+	}
+	if len(r.IndexErrors) > 0 {
+		fmt.Fprintln(w, "\nIndex errors:")
+		for _, e := range r.IndexErrors {
+			fmt.Fprintf(w, "  %s\n", e)
 		}
 	}
 }
@@ -1,6 +1,10 @@
 package exporter
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,7 +26,7 @@ func TestExporter(t *testing.T) {
 			},
 		}
 
-		result, err := exp.Export(state, false)
+		result, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -44,7 +48,7 @@ func TestExporter(t *testing.T) {
 			Transcripts: map[string][]TranscriptEntry{},
 		}
 
-		result, err := exp.Export(state, false)
+		result, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -68,7 +72,7 @@ func TestExporter(t *testing.T) {
 			Transcripts: map[string][]TranscriptEntry{},
 		}
 
-		result, err := exp.Export(state, false)
+		result, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -88,6 +92,28 @@ func TestExporter(t *testing.T) {
 		}
 	})
 
+	t.Run("leaves out deleted documents", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Kept", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "This is a long enough note to export"},
+				"doc2": {ID: "doc2", Title: "Trashed", CreatedAt: "2026-01-21T10:00:00Z", DeletedAt: "2026-01-22T10:00:00Z", NotesMarkdown: "This is a long enough note to export"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.Written != 1 || len(result.Documents) != 1 || result.Documents[0].DocumentID != "doc1" {
+			t.Errorf("Expected only doc1 to be exported, got %+v", result.Documents)
+		}
+	})
+
 	t.Run("skips documents with neither notes nor transcript", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		exp := NewExporter(tmpDir)
@@ -99,7 +125,7 @@ func TestExporter(t *testing.T) {
 			Transcripts: map[string][]TranscriptEntry{},
 		}
 
-		result, err := exp.Export(state, false)
+		result, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -138,7 +164,7 @@ Meeting ID: doc1
 			Transcripts: map[string][]TranscriptEntry{},
 		}
 
-		_, err := exp.Export(state, false)
+		_, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -172,7 +198,7 @@ Meeting ID: doc1
 		}
 
 		// First export
-		result1, err := exp.Export(state, false)
+		result1, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -181,7 +207,7 @@ Meeting ID: doc1
 		}
 
 		// Second export with same content
-		result2, err := exp.Export(state, false)
+		result2, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -193,6 +219,405 @@ Meeting ID: doc1
 		}
 	})
 
+	t.Run("skips unchanged documents without reading them", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Change the contents but keep size and mtime; a disk comparison would rewrite it
+		path := filepath.Join(tmpDir, "2026-01-21_Test.md")
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := os.ReadFile(path)
+		if err := os.WriteFile(path, bytes.ToUpper(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Skipped != 1 || len(result.Errors) != 0 {
+			t.Errorf("Expected 1 skipped and no errors, got %+v", result)
+		}
+	})
+
+	t.Run("rewrites documents edited outside granary", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		path := filepath.Join(tmpDir, "2026-01-21_Test.md")
+		if err := os.WriteFile(path, []byte("edited by hand"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Written != 1 {
+			t.Errorf("Expected edited file to be rewritten, got %+v", result)
+		}
+	})
+
+	t.Run("rewrites documents when the notes selection changes", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Typed by me", Panels: []DocumentPanel{{Content: json.RawMessage(`"Some AI notes here"`)}}},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		exp.Format = FormatOptions{Notes: NotesMine}
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Written != 1 {
+			t.Errorf("Expected file to be rewritten, got %+v", result)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "2026-01-21_Test.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), "AI-Generated Notes") {
+			t.Errorf("Expected only my notes:\n%s", content)
+		}
+	})
+
+	t.Run("falls back to disk comparison without state file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := os.Remove(filepath.Join(tmpDir, StateFilename)); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Skipped != 1 {
+			t.Errorf("Expected 1 skipped, got %+v", result)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, StateFilename)); err != nil {
+			t.Error("Expected state file to be recreated")
+		}
+	})
+
+	t.Run("records per-document actions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Documents) != 1 {
+			t.Fatalf("Expected 1 document result, got %d", len(result.Documents))
+		}
+		got := result.Documents[0]
+		if got.Action != ActionWritten || got.Filename != "2026-01-21_Test.md" {
+			t.Errorf("Unexpected document result: %+v", got)
+		}
+
+		result, err = exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Documents[0].Action != ActionSkipped {
+			t.Errorf("Expected skipped action on second export, got %s", result.Documents[0].Action)
+		}
+	})
+
+	t.Run("reports transcript stats", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z"},
+			},
+			Transcripts: map[string][]TranscriptEntry{
+				"doc1": {
+					{Source: "system", Text: "Hello there", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:10Z"},
+					{Source: "microphone", Text: "Hi", StartTimestamp: "2026-01-21T10:00:10Z", EndTimestamp: "2026-01-21T10:00:15Z"},
+				},
+			},
+		}
+
+		for _, action := range []DocumentAction{ActionWritten, ActionSkipped} {
+			result, err := exp.Export(state)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got := result.Documents[0]
+			if got.Action != action {
+				t.Fatalf("Expected %s, got %s", action, got.Action)
+			}
+			if got.Stats == nil || got.Stats.Words != 3 || got.Stats.DurationSeconds != 15 {
+				t.Errorf("Unexpected stats for %s document: %+v", action, got.Stats)
+			}
+		}
+	})
+
+	t.Run("reports stats of a transcript kept from the file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{
+				"doc1": {
+					{Source: "system", Text: "Hello there"},
+					{Source: "microphone", Text: "Hi"},
+				},
+			},
+		}
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Once the cache drops the transcript, the file keeps it: the first
+		// run finds the content unchanged, the next skips on the state
+		// without reading the file, which a same-size edit would change
+		state.Transcripts = map[string][]TranscriptEntry{}
+		path := filepath.Join(tmpDir, "2026-01-21_Test.md")
+		for run := range 2 {
+			if run == 1 {
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				content, _ := os.ReadFile(path)
+				if err := os.WriteFile(path, bytes.Replace(content, []byte("Hello there"), []byte("Hello_there"), 1), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			result, err := exp.Export(state)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got := result.Documents[0]
+			if got.Action != ActionSkipped {
+				t.Fatalf("Expected skipped, got %s", got.Action)
+			}
+			if got.Stats == nil || got.Stats.Words != 3 || got.Stats.Turns != 2 {
+				t.Errorf("Expected stats of the kept transcript, got %+v", got.Stats)
+			}
+		}
+	})
+
+	t.Run("forgets the cache fingerprint until a run succeeds", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Beta", CreatedAt: "2026-01-22T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		// A directory in the way makes the document fail to export
+		blocked := filepath.Join(tmpDir, "2026-01-22_Beta.md")
+		if err := os.MkdirAll(blocked, 0755); err != nil {
+			t.Fatal(err)
+		}
+		exportState := &ExportState{Cache: &CacheFingerprint{Path: "cache-v6.json", Size: 1, SHA256: "abc"}}
+		exp := NewExporter(tmpDir)
+		exp.State = exportState
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Errors) != 1 {
+			t.Fatalf("Expected the document to fail, got %+v", result)
+		}
+
+		saved, err := LoadExportState(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exportState.Cache != nil || saved.Cache != nil {
+			t.Fatalf("Expected the fingerprint to be dropped after a failed run, got %+v", saved.Cache)
+		}
+
+		if err := os.Remove(blocked); err != nil {
+			t.Fatal(err)
+		}
+		exp.State = saved
+		result, err = exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Written != 1 {
+			t.Errorf("Expected the document to be written on the next run, got %+v", result)
+		}
+		if _, err := os.Stat(blocked); err != nil {
+			t.Errorf("Expected the file to be recreated: %v", err)
+		}
+	})
+
+	t.Run("logs written documents", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		var logs bytes.Buffer
+		exp := NewExporter(tmpDir)
+		exp.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !strings.Contains(logs.String(), `msg="wrote document" file=2026-01-21_Test.md`) {
+			t.Errorf("Expected written document to be logged, got %q", logs.String())
+		}
+	})
+
+	t.Run("logs removed transcript entries at debug level", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		var logs bytes.Buffer
+		exp := NewExporter(tmpDir)
+		exp.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Test", CreatedAt: "2026-01-21T10:00:00Z"},
+			},
+			Transcripts: map[string][]TranscriptEntry{
+				"doc1": {
+					{ID: "t1", Source: "system", Text: "Hel", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:01Z"},
+					{ID: "t2", Source: "system", Text: "Hello", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:02Z", IsFinal: true},
+					{ID: "t2", Source: "system", Text: "Hello", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:02Z", IsFinal: true},
+				},
+			},
+		}
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !strings.Contains(logs.String(), `msg="removed transcript entries" file=2026-01-21_Test.md interim=1 duplicates=1`) {
+			t.Errorf("Expected removed entries to be logged, got %q", logs.String())
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, "2026-01-21_Test.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(string(content), "**Them:**") != 1 {
+			t.Errorf("Expected a single transcript line:\n%s", content)
+		}
+	})
+
+	t.Run("exports concurrently in deterministic order", func(t *testing.T) {
+		state := &CacheState{
+			Documents:   map[string]Document{},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+		for i := 0; i < 50; i++ {
+			id := fmt.Sprintf("doc%02d", i)
+			state.Documents[id] = Document{ID: id, Title: fmt.Sprintf("Meeting %02d", i), CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"}
+			if i%2 == 0 {
+				state.Transcripts[id] = []TranscriptEntry{{Text: "Hello", Source: "microphone"}}
+			}
+		}
+
+		var previousLogs string
+		for run := 0; run < 3; run++ {
+			tmpDir := t.TempDir()
+			var logs bytes.Buffer
+			exp := NewExporter(tmpDir)
+			exp.Jobs = 8
+			exp.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+
+			result, err := exp.Export(state)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Written != 50 {
+				t.Fatalf("Expected 50 written, got %d", result.Written)
+			}
+			for i, doc := range result.Documents {
+				want := fmt.Sprintf("2026-01-21_Meeting %02d.md", i)
+				if doc.Filename != want {
+					t.Fatalf("Expected document %d to be %s, got %s", i, want, doc.Filename)
+				}
+			}
+			if run > 0 && logs.String() != previousLogs {
+				t.Error("Expected identical log output across runs")
+			}
+			previousLogs = logs.String()
+		}
+	})
+
 	t.Run("creates output directory if not exists", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		outputDir := filepath.Join(tmpDir, "nested", "output", "dir")
@@ -205,7 +630,7 @@ Meeting ID: doc1
 			Transcripts: map[string][]TranscriptEntry{},
 		}
 
-		_, err := exp.Export(state, false)
+		_, err := exp.Export(state)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
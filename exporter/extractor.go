@@ -57,6 +57,69 @@ func ExtractTranscriptFromMarkdown(content string) []TranscriptEntry {
 	return entries
 }
 
+// Headings of the sections granary writes after the header.
+const (
+	myNotesHeading    = "## My Notes"
+	aiNotesHeading    = "## AI-Generated Notes"
+	statsHeading      = "## Stats"
+	transcriptHeading = "## Transcript"
+)
+
+// ExtractNotesFromMarkdown extracts the user's and the AI-generated notes
+// from an exported markdown file. A section ends at the "---" separator
+// before the next section granary writes, so separators inside the notes
+// themselves are kept. Missing sections are returned as empty strings.
+func ExtractNotesFromMarkdown(content string) (mine, ai string) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	return extractSection(lines, myNotesHeading), extractSection(lines, aiNotesHeading)
+}
+
+// extractSection returns the trimmed body of the section with heading.
+func extractSection(lines []string, heading string) string {
+	start := -1
+	for i, line := range lines {
+		if line == heading {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	end := len(lines)
+	for i := start; i < len(lines); i++ {
+		if lines[i] == "---" && isSectionHeading(nextNonBlank(lines, i+1)) {
+			end = i
+			break
+		}
+		if isSectionHeading(lines[i]) {
+			end = i
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+}
+
+// isSectionHeading reports whether line starts one of granary's sections.
+func isSectionHeading(line string) bool {
+	switch line {
+	case myNotesHeading, aiNotesHeading, statsHeading, transcriptHeading:
+		return true
+	}
+	return false
+}
+
+// nextNonBlank returns the first non-blank line at or after i.
+func nextNonBlank(lines []string, i int) string {
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			return lines[i]
+		}
+	}
+	return ""
+}
+
 // SpeakerToSource maps a speaker label back to a source.
 func SpeakerToSource(speaker string) string {
 	switch speaker {
@@ -68,3 +131,34 @@ func SpeakerToSource(speaker string) string {
 		return strings.ToLower(speaker)
 	}
 }
+
+// ExportHeader holds the header fields written at the top of an exported file.
+type ExportHeader struct {
+	Title     string
+	Date      string
+	MeetingID string
+}
+
+// ExtractHeaderFromMarkdown parses the title, date and meeting ID from the
+// header of an exported markdown file. Missing fields are left empty.
+func ExtractHeaderFromMarkdown(content string) ExportHeader {
+	var header ExportHeader
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "---" && header.Title != "" {
+			// End of header
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "# ") && header.Title == "":
+			header.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		case strings.HasPrefix(line, "Date: ") && header.Date == "":
+			header.Date = strings.TrimSpace(strings.TrimPrefix(line, "Date: "))
+		case strings.HasPrefix(line, "Meeting ID: ") && header.MeetingID == "":
+			header.MeetingID = strings.TrimSpace(strings.TrimPrefix(line, "Meeting ID: "))
+		}
+	}
+
+	return header
+}
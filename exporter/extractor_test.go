@@ -1,6 +1,7 @@
 package exporter
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -182,6 +183,34 @@ Just notes, no transcript.
 	})
 }
 
+func TestExtractNotesFromMarkdown(t *testing.T) {
+	t.Run("round-trips both notes sections", func(t *testing.T) {
+		doc := &Document{
+			ID:            "doc1",
+			Title:         "Notes",
+			NotesMarkdown: "My own\n\n---\n\nwith a rule",
+			Panels:        []DocumentPanel{{Content: json.RawMessage(`"- AI point"`)}},
+		}
+		transcript := []TranscriptEntry{{Source: "system", Text: "Hi", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:05Z"}}
+		content := FormatDocumentMarkdownWithOptions(doc, transcript, FormatOptions{Stats: true})
+
+		mine, ai := ExtractNotesFromMarkdown(content)
+		if mine != "My own\n\n---\n\nwith a rule" {
+			t.Errorf("Unexpected my notes: %q", mine)
+		}
+		if ai != "- AI point" {
+			t.Errorf("Unexpected AI notes: %q", ai)
+		}
+	})
+
+	t.Run("returns empty strings for missing sections", func(t *testing.T) {
+		mine, ai := ExtractNotesFromMarkdown("# Title\nMeeting ID: x\n\n---\n\n## Transcript\n\n**Me:** Hi\n")
+		if mine != "" || ai != "" {
+			t.Errorf("Expected no notes, got %q and %q", mine, ai)
+		}
+	})
+}
+
 func TestSpeakerToSource(t *testing.T) {
 	tests := []struct {
 		speaker  string
@@ -202,3 +231,34 @@ func TestSpeakerToSource(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractHeaderFromMarkdown(t *testing.T) {
+	t.Run("roundtrip format then extract", func(t *testing.T) {
+		doc := &Document{
+			ID:            "abc-123",
+			Title:         "Weekly Sync",
+			CreatedAt:     "2026-01-21T10:00:00Z",
+			NotesMarkdown: "Meeting ID: not-a-header",
+		}
+
+		header := ExtractHeaderFromMarkdown(FormatDocumentMarkdown(doc, nil))
+
+		if header.Title != "Weekly Sync" {
+			t.Errorf("Expected title 'Weekly Sync', got %q", header.Title)
+		}
+		if header.Date != "2026-01-21 10:00" {
+			t.Errorf("Expected date '2026-01-21 10:00', got %q", header.Date)
+		}
+		if header.MeetingID != "abc-123" {
+			t.Errorf("Expected meeting ID 'abc-123', got %q", header.MeetingID)
+		}
+	})
+
+	t.Run("returns empty fields when header is missing", func(t *testing.T) {
+		header := ExtractHeaderFromMarkdown("Just some text")
+
+		if header != (ExportHeader{}) {
+			t.Errorf("Expected empty header, got %+v", header)
+		}
+	})
+}
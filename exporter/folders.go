@@ -0,0 +1,196 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FolderDir converts a Granola folder title to a relative directory path.
+// Slashes in the title nest directories, so "Customers/Acme" becomes two
+// levels. Unsafe characters are removed from each part, and parts that would
+// clash with granary's own folders are dropped.
+func FolderDir(title string) string {
+	var parts []string
+	for _, part := range strings.Split(title, "/") {
+		part = strings.TrimSpace(removeUnsafeChars(part))
+		// Never let a folder name climb out of the output directory or hide itself
+		part = strings.TrimLeft(part, ".")
+		if part != "" && part != ArchiveDirName && part != SeriesDirName {
+			parts = append(parts, part)
+		}
+	}
+	return filepath.Join(parts...)
+}
+
+// folderPaths returns the path a document is written to when folders are
+// mirrored, and the paths in its other folders that link to it. Documents
+// outside any folder stay at the top level.
+func folderPaths(doc *Document, filename string) (string, []string) {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, folder := range doc.Folders {
+		dir := FolderDir(folder)
+		if dir != "" && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return filename, nil
+	}
+
+	var links []string
+	for _, dir := range dirs[1:] {
+		links = append(links, filepath.Join(dir, filename))
+	}
+	return filepath.Join(dirs[0], filename), links
+}
+
+// relocateAll moves the files of documents whose filename changed since the
+// last run. It runs before any document is written, so a document taking
+// over a filename can't write it while the file there still waits to be
+// moved. A move blocked by another file waiting to be moved out of its way
+// is retried once that file has gone. Returns the previous filename of each
+// moved document, and the error of each document that failed to move.
+func (e *Exporter) relocateAll(docs []Document, filenames map[string]string, previous map[string]*DocumentState) (map[string]string, map[string]error) {
+	moved := make(map[string]string)
+	failed := make(map[string]error)
+
+	pending := docs
+	for len(pending) > 0 {
+		var blocked []Document
+		for _, doc := range pending {
+			from, err := e.relocate(doc.ID, filenames[doc.ID], previous[doc.ID])
+			switch {
+			case err != nil:
+				failed[doc.ID] = err
+			case from != "":
+				moved[doc.ID] = from
+			default:
+				blocked = append(blocked, doc)
+			}
+		}
+		if len(blocked) == len(pending) {
+			break
+		}
+		pending = blocked
+	}
+
+	return moved, failed
+}
+
+// relocate moves the file written for document id on a previous run to
+// filename, so a document that was renamed or moved between folders keeps
+// any transcript preserved in its file. Files whose Meeting ID header names
+// another document are left alone. Returns the old filename if the file was
+// moved.
+func (e *Exporter) relocate(id, filename string, previous *DocumentState) (string, error) {
+	if previous == nil || previous.Filename == "" || previous.Filename == filename {
+		return "", nil
+	}
+
+	oldPath := filepath.Join(e.OutputDir, previous.Filename)
+	newPath := filepath.Join(e.OutputDir, filename)
+
+	info, err := os.Lstat(oldPath)
+	if err != nil || !info.Mode().IsRegular() || !belongsTo(oldPath, id) {
+		return "", nil
+	}
+	if existing, err := os.Lstat(newPath); err == nil {
+		if existing.Mode()&os.ModeSymlink == 0 {
+			// Never overwrite; the old file is left for prune to deal with
+			return "", nil
+		}
+		// The new primary folder used to link to the old one
+		if err := os.Remove(newPath); err != nil {
+			return "", fmt.Errorf("failed to replace link %s: %w", filename, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create folder: %w", err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", previous.Filename, err)
+	}
+
+	return previous.Filename, nil
+}
+
+// belongsTo reports whether the exported file at path was written for the
+// document id, going by its Meeting ID header.
+func belongsTo(path, id string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return ExtractHeaderFromMarkdown(string(content)).MeetingID == id
+}
+
+// syncLinks points each of links at filename and removes stale links that
+// are no longer wanted. Only symlinks are ever removed or replaced. Returns
+// the directories links were removed from.
+func (e *Exporter) syncLinks(filename string, links, stale []string) ([]string, error) {
+	wanted := make(map[string]bool, len(links))
+	for _, link := range links {
+		wanted[link] = true
+
+		linkPath := filepath.Join(e.OutputDir, link)
+		target, err := filepath.Rel(filepath.Dir(linkPath), filepath.Join(e.OutputDir, filename))
+		if err != nil {
+			return nil, fmt.Errorf("failed to link %s: %w", link, err)
+		}
+
+		if current, err := os.Readlink(linkPath); err == nil {
+			if current == target {
+				continue
+			}
+			if err := os.Remove(linkPath); err != nil {
+				return nil, fmt.Errorf("failed to update link %s: %w", link, err)
+			}
+		} else if _, err := os.Lstat(linkPath); err == nil {
+			return nil, fmt.Errorf("failed to link %s: a file already exists there", link)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(linkPath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create folder: %w", err)
+		}
+		if err := os.Symlink(target, linkPath); err != nil {
+			return nil, fmt.Errorf("failed to link %s: %w", link, err)
+		}
+	}
+
+	var vacated []string
+	for _, link := range stale {
+		if wanted[link] {
+			continue
+		}
+		linkPath := filepath.Join(e.OutputDir, link)
+		info, err := os.Lstat(linkPath)
+		if errors.Is(err, os.ErrNotExist) || (err == nil && info.Mode()&os.ModeSymlink == 0) {
+			continue
+		}
+		if err := os.Remove(linkPath); err != nil {
+			return nil, fmt.Errorf("failed to remove link %s: %w", link, err)
+		}
+		vacated = append(vacated, filepath.Dir(linkPath))
+	}
+
+	return vacated, nil
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, stopping
+// at the output directory root. Export only calls it once all workers are
+// done, so a folder can't disappear while another document is being written
+// to it.
+func removeEmptyDirs(root, dir string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
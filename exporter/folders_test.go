@@ -0,0 +1,270 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFolderDir(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Customers", "Customers"},
+		{"Customers/Acme", filepath.Join("Customers", "Acme")},
+		{" 1:1s ", "11s"},
+		{"../Secrets", "Secrets"},
+		{".hidden", "hidden"},
+		{"_archive", ""},
+		{"_series", ""},
+		{"//", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := FolderDir(tt.title); got != tt.want {
+				t.Errorf("FolderDir(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportMirrorFolders(t *testing.T) {
+	newState := func(folders map[string][]string) *CacheState {
+		state := &CacheState{
+			Documents: map[string]Document{
+				"doc1": {ID: "doc1", Title: "Kickoff", CreatedAt: "2025-01-24T10:00:00Z", NotesMarkdown: "Kickoff notes here"},
+				"doc2": {ID: "doc2", Title: "Sync", CreatedAt: "2025-01-25T10:00:00Z", NotesMarkdown: "Sync notes here"},
+				"doc3": {ID: "doc3", Title: "Loose", CreatedAt: "2025-01-26T10:00:00Z", NotesMarkdown: "Loose notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+		for id, f := range folders {
+			doc := state.Documents[id]
+			doc.Folders = f
+			state.Documents[id] = doc
+		}
+		return state
+	}
+
+	t.Run("writes documents into folder directories", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.MirrorFolders = true
+
+		result, err := exp.Export(newState(map[string][]string{
+			"doc1": {"Customers/Acme"},
+			"doc2": {"1:1s", "Hiring"},
+		}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Written != 3 {
+			t.Errorf("Expected 3 written, got %+v", result)
+		}
+
+		for _, path := range []string{
+			filepath.Join("Customers", "Acme", "2025-01-24_Kickoff.md"),
+			filepath.Join("11s", "2025-01-25_Sync.md"),
+			"2025-01-26_Loose.md",
+		} {
+			info, err := os.Lstat(filepath.Join(tmpDir, path))
+			if err != nil || !info.Mode().IsRegular() {
+				t.Errorf("Expected regular file %s: %v", path, err)
+			}
+		}
+
+		link := filepath.Join(tmpDir, "Hiring", "2025-01-25_Sync.md")
+		target, err := os.Readlink(link)
+		if err != nil {
+			t.Fatalf("Expected symlink in secondary folder: %v", err)
+		}
+		if target != filepath.Join("..", "11s", "2025-01-25_Sync.md") {
+			t.Errorf("Expected relative link, got %q", target)
+		}
+		content, err := os.ReadFile(link)
+		if err != nil || !strings.Contains(string(content), "Sync notes here") {
+			t.Errorf("Expected link to resolve to the document: %v", err)
+		}
+
+		files, err := ScanExports(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 3 {
+			t.Errorf("Expected links to be ignored by ScanExports, got %d files", len(files))
+		}
+	})
+
+	t.Run("moves files when documents change folders", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.MirrorFolders = true
+
+		if _, err := exp.Export(newState(map[string][]string{
+			"doc1": {"Customers"},
+			"doc2": {"Alpha", "Beta"},
+		})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Keep a transcript only in the exported file, like one Granola purged
+		oldPath := filepath.Join(tmpDir, "Customers", "2025-01-24_Kickoff.md")
+		content, err := os.ReadFile(oldPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, oldPath, string(content)+"\n---\n\n## Transcript\n\n**Me:** Preserved line\n")
+
+		// doc1 moves folder; doc2 leaves Alpha so Beta becomes its primary
+		result, err := exp.Export(newState(map[string][]string{
+			"doc1": {"Partners"},
+			"doc2": {"Beta"},
+		}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Errors) != 0 {
+			t.Fatalf("Unexpected errors: %+v", result.Errors)
+		}
+
+		moved, err := os.ReadFile(filepath.Join(tmpDir, "Partners", "2025-01-24_Kickoff.md"))
+		if err != nil {
+			t.Fatalf("Expected moved file: %v", err)
+		}
+		if !strings.Contains(string(moved), "Preserved line") {
+			t.Error("Expected preserved transcript to survive the move")
+		}
+
+		info, err := os.Lstat(filepath.Join(tmpDir, "Beta", "2025-01-25_Sync.md"))
+		if err != nil || !info.Mode().IsRegular() {
+			t.Errorf("Expected Beta to hold the file instead of a link: %v", err)
+		}
+
+		for _, dir := range []string{"Customers", "Alpha"} {
+			if _, err := os.Stat(filepath.Join(tmpDir, dir)); !os.IsNotExist(err) {
+				t.Errorf("Expected empty folder %s to be removed", dir)
+			}
+		}
+	})
+
+	t.Run("moves files back when mirroring is turned off", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.MirrorFolders = true
+		state := newState(map[string][]string{"doc2": {"Alpha", "Beta"}})
+
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		exp.MirrorFolders = false
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := os.Lstat(filepath.Join(tmpDir, "2025-01-25_Sync.md")); err != nil {
+			t.Errorf("Expected file back at the top level: %v", err)
+		}
+		for _, dir := range []string{"Alpha", "Beta"} {
+			if _, err := os.Lstat(filepath.Join(tmpDir, dir)); !os.IsNotExist(err) {
+				t.Errorf("Expected folder %s to be removed", dir)
+			}
+		}
+	})
+
+	t.Run("never replaces a regular file with a link", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, "Beta", "2025-01-25_Sync.md"), "my own file")
+
+		exp := NewExporter(tmpDir)
+		exp.MirrorFolders = true
+		result, err := exp.Export(newState(map[string][]string{"doc2": {"Alpha", "Beta"}}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Errors) != 1 {
+			t.Errorf("Expected a link error, got %+v", result.Errors)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "Beta", "2025-01-25_Sync.md"))
+		if err != nil || string(content) != "my own file" {
+			t.Errorf("Expected existing file to be left alone, got %q", content)
+		}
+	})
+
+	t.Run("moves renamed files before writing new ones", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Jobs = 4
+
+		transcript := []TranscriptEntry{{ID: "t1", Text: "Only in the first export", Source: "system", StartTimestamp: "2026-01-21T10:00:05Z", IsFinal: true}}
+		state := &CacheState{
+			Documents: map[string]Document{
+				"docA": {ID: "docA", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Notes about the zebra"},
+			},
+			Transcripts: map[string][]TranscriptEntry{"docA": transcript},
+		}
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// docA is retitled and evicted from the cache transcripts; a new
+		// untitled document takes its old filename
+		state = &CacheState{
+			Documents: map[string]Document{
+				"docA": {ID: "docA", Title: "Zebra", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Notes about the zebra"},
+				"docB": {ID: "docB", CreatedAt: "2026-01-21T11:00:00Z", NotesMarkdown: "Brand new meeting notes"},
+			},
+			Transcripts: map[string][]TranscriptEntry{},
+		}
+		result, err := exp.Export(state)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Written != 2 || len(result.Errors) != 0 {
+			t.Errorf("Expected both documents written, got %+v", result)
+		}
+
+		zebra, err := os.ReadFile(filepath.Join(tmpDir, "2026-01-21_Zebra.md"))
+		if err != nil || !strings.Contains(string(zebra), "Only in the first export") {
+			t.Errorf("Expected the moved file to keep its transcript, got %q (%v)", zebra, err)
+		}
+		untitled, err := os.ReadFile(filepath.Join(tmpDir, "2026-01-21_Untitled.md"))
+		if err != nil {
+			t.Fatalf("Expected the new document's file: %v", err)
+		}
+		if !strings.Contains(string(untitled), "Meeting ID: docB") || strings.Contains(string(untitled), "## Transcript") {
+			t.Errorf("Expected docB's own content, got:\n%s", untitled)
+		}
+
+		saved, err := LoadExportState(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ds := saved.Documents["docB"]; ds.Filename != "2026-01-21_Untitled.md" || ds.Transcript {
+			t.Errorf("Unexpected state for docB: %+v", ds)
+		}
+	})
+
+	t.Run("leaves files that belong to another meeting", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, "2025-01-24_Old.md"), "# Old\nMeeting ID: someone-else\n\n---\n\n## Transcript\n\n**Them:** Not yours\n")
+
+		exp := NewExporter(tmpDir)
+		exp.State = &ExportState{Documents: map[string]DocumentState{"doc1": {Filename: "2025-01-24_Old.md"}}}
+		if _, err := exp.Export(newState(nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(tmpDir, "2025-01-24_Old.md")); err != nil {
+			t.Errorf("Expected the other meeting's file to stay: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(tmpDir, "2025-01-24_Kickoff.md"))
+		if err != nil || strings.Contains(string(content), "Not yours") {
+			t.Errorf("Expected a fresh file without the other transcript, got %q (%v)", content, err)
+		}
+	})
+}
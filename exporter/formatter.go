@@ -6,8 +6,69 @@ import (
 	"time"
 )
 
-// FormatDocumentMarkdown formats a document and its transcript as markdown.
+// Note sections that FormatOptions.Notes can select.
+const (
+	NotesBoth = "both"
+	NotesAI   = "ai"
+	NotesMine = "mine"
+)
+
+// FormatOptions controls how documents are rendered.
+type FormatOptions struct {
+	// Notes selects which notes sections are written: NotesBoth (the
+	// default), NotesAI for only the AI-generated notes, or NotesMine for
+	// only the notes typed during the meeting.
+	Notes string
+	// FrontMatter adds a YAML front matter block with the document's
+	// metadata, for tools such as Obsidian or static site generators.
+	FrontMatter bool
+	// MergeTurns combines consecutive transcript entries from the same
+	// speaker into one paragraph, see ConsolidateTranscript.
+	MergeTurns bool
+	// MergeGap is the longest pause within a merged turn. Zero or less
+	// merges regardless of pauses.
+	MergeGap time.Duration
+	// Stats adds a "Stats" section with talk time and word counts per
+	// speaker before the transcript, and the same numbers to front matter.
+	Stats bool
+}
+
+// ValidateNotes returns an error if notes is not a supported notes selection.
+func ValidateNotes(notes string) error {
+	switch notes {
+	case "", NotesBoth, NotesAI, NotesMine:
+		return nil
+	default:
+		return fmt.Errorf("unsupported notes selection %q (expected %q, %q or %q)", notes, NotesBoth, NotesAI, NotesMine)
+	}
+}
+
+// notes returns the user's and the AI notes selected by o, with blank
+// notes returned as empty strings.
+func (o FormatOptions) notes(doc *Document) (mine, ai string) {
+	if o.Notes != NotesAI {
+		mine = doc.GetNotes()
+	}
+	if o.Notes != NotesMine {
+		ai = doc.GetAINotes()
+	}
+	if strings.TrimSpace(mine) == "" {
+		mine = ""
+	}
+	if strings.TrimSpace(ai) == "" {
+		ai = ""
+	}
+	return mine, ai
+}
+
+// FormatDocumentMarkdown formats a document and its transcript as markdown
+// with the default options.
 func FormatDocumentMarkdown(doc *Document, transcript []TranscriptEntry) string {
+	return FormatDocumentMarkdownWithOptions(doc, transcript, FormatOptions{})
+}
+
+// FormatDocumentMarkdownWithOptions formats a document and its transcript as markdown.
+func FormatDocumentMarkdownWithOptions(doc *Document, transcript []TranscriptEntry, opts FormatOptions) string {
 	var lines []string
 
 	title := doc.Title
@@ -17,28 +78,95 @@ func FormatDocumentMarkdown(doc *Document, transcript []TranscriptEntry) string
 
 	dateStr := FormatDate(doc.CreatedAt)
 
+	var stats *TranscriptStats
+	if opts.Stats {
+		stats = ComputeTranscriptStats(transcript)
+	}
+
+	if opts.FrontMatter {
+		lines = append(lines, formatFrontMatter(doc, stats)...)
+	}
+
 	lines = append(lines, fmt.Sprintf("# %s", title))
 	lines = append(lines, fmt.Sprintf("Date: %s", dateStr))
 	lines = append(lines, fmt.Sprintf("Meeting ID: %s", doc.ID))
+	if doc.UpdatedAt != "" {
+		lines = append(lines, fmt.Sprintf("Updated: %s", FormatDate(doc.UpdatedAt)))
+	}
+	if event := doc.GoogleCalendarEvent; event != nil {
+		if scheduled := FormatSchedule(event.Start, event.End); scheduled != "" {
+			lines = append(lines, fmt.Sprintf("Scheduled: %s", scheduled))
+		}
+		if url := event.ConferenceURL(); url != "" {
+			lines = append(lines, fmt.Sprintf("Conference: %s", url))
+		}
+		if event.Summary != "" {
+			lines = append(lines, fmt.Sprintf("Calendar event: %s", event.Summary))
+		}
+	}
+	if len(doc.Folders) > 0 {
+		lines = append(lines, fmt.Sprintf("Folders: %s", strings.Join(doc.Folders, ", ")))
+	}
+	if doc.WorkspaceID != "" {
+		lines = append(lines, fmt.Sprintf("Workspace: %s", doc.WorkspaceID))
+	}
+	if attendees := doc.Attendees(); len(attendees) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, "Attendees:")
+		for _, a := range attendees {
+			if a.Organizer {
+				lines = append(lines, fmt.Sprintf("- %s (organizer)", a))
+			} else {
+				lines = append(lines, fmt.Sprintf("- %s", a))
+			}
+		}
+	}
 	lines = append(lines, "")
 	lines = append(lines, "---")
 	lines = append(lines, "")
 
-	// Add AI-generated notes if they exist
-	notes := doc.GetNotes()
-	hasNotes := notes != "" && strings.TrimSpace(notes) != ""
+	// Add the user's own notes, then the AI-generated notes, if they exist
+	mine, ai := opts.notes(doc)
+	hasSection := false
 
-	if hasNotes {
+	if mine != "" {
+		lines = append(lines, "## My Notes")
+		lines = append(lines, "")
+		lines = append(lines, mine)
+		lines = append(lines, "")
+		hasSection = true
+	}
+
+	if ai != "" {
+		if hasSection {
+			lines = append(lines, "---")
+			lines = append(lines, "")
+		}
 		lines = append(lines, "## AI-Generated Notes")
 		lines = append(lines, "")
-		lines = append(lines, notes)
+		lines = append(lines, ai)
 		lines = append(lines, "")
+		hasSection = true
+	}
+
+	// Stats come before the transcript so the transcript stays last in the file
+	if stats != nil {
+		if hasSection {
+			lines = append(lines, "---")
+			lines = append(lines, "")
+		}
+		lines = append(lines, formatStatsSection(stats)...)
+		hasSection = true
+	}
+
+	if opts.MergeTurns {
+		transcript = ConsolidateTranscript(transcript, opts.MergeGap)
 	}
 
 	// Add transcript if it exists
 	hasTranscript := len(transcript) > 0
 	if hasTranscript {
-		if hasNotes {
+		if hasSection {
 			lines = append(lines, "---")
 			lines = append(lines, "")
 		}
@@ -63,9 +191,18 @@ func FormatDocumentMarkdown(doc *Document, transcript []TranscriptEntry) string
 // FormatDate parses an ISO8601 timestamp and formats it as "YYYY-MM-DD HH:MM".
 // Returns "Unknown date" if parsing fails.
 func FormatDate(timestamp string) string {
-	if timestamp == "" {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
 		return "Unknown date"
 	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// parseTimestamp parses an ISO8601 timestamp as written by Granola.
+func parseTimestamp(timestamp string) (time.Time, bool) {
+	if timestamp == "" {
+		return time.Time{}, false
+	}
 
 	// Try parsing with various formats
 	formats := []string{
@@ -75,49 +212,54 @@ func FormatDate(timestamp string) string {
 		"2006-01-02T15:04:05Z",
 	}
 
-	var t time.Time
-	var err error
 	for _, format := range formats {
-		t, err = time.Parse(format, timestamp)
-		if err == nil {
-			break
+		if t, err := time.Parse(format, timestamp); err == nil {
+			return t, true
 		}
 	}
-
-	if err != nil {
-		return "Unknown date"
-	}
-
-	return t.Format("2006-01-02 15:04")
+	return time.Time{}, false
 }
 
-// FormatDateForFilename parses an ISO8601 timestamp and formats it as "YYYY-MM-DD".
-// Returns "unknown-date" if parsing fails.
-func FormatDateForFilename(timestamp string) string {
-	if timestamp == "" {
-		return "unknown-date"
+// FormatSchedule formats a calendar event's start and end, such as
+// "2026-01-21 10:00 - 10:30". The end date is only repeated when the event
+// spans days. Returns "" if the start is unknown.
+func FormatSchedule(start, end *CalendarTime) string {
+	startStr := formatCalendarTime(start)
+	if startStr == "" {
+		return ""
 	}
-
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		"2006-01-02T15:04:05.000Z",
-		"2006-01-02T15:04:05Z",
+	endStr := formatCalendarTime(end)
+	if endStr == "" || endStr == startStr {
+		return startStr
 	}
+	if len(startStr) > 10 && len(endStr) > 10 && startStr[:10] == endStr[:10] {
+		endStr = endStr[11:]
+	}
+	return startStr + " - " + endStr
+}
 
-	var t time.Time
-	var err error
-	for _, format := range formats {
-		t, err = time.Parse(format, timestamp)
-		if err == nil {
-			break
+// formatCalendarTime formats a calendar time as "YYYY-MM-DD HH:MM", or just
+// the date for all-day events. Returns "" if it can't be parsed.
+func formatCalendarTime(t *CalendarTime) string {
+	if t == nil {
+		return ""
+	}
+	if t.DateTime != "" {
+		if formatted := FormatDate(t.DateTime); formatted != "Unknown date" {
+			return formatted
 		}
+		return ""
 	}
+	return t.Date
+}
 
-	if err != nil {
+// FormatDateForFilename parses an ISO8601 timestamp and formats it as "YYYY-MM-DD".
+// Returns "unknown-date" if parsing fails.
+func FormatDateForFilename(timestamp string) string {
+	t, ok := parseTimestamp(timestamp)
+	if !ok {
 		return "unknown-date"
 	}
-
 	return t.Format("2006-01-02")
 }
 
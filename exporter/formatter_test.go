@@ -1,6 +1,7 @@
 package exporter
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -25,8 +26,8 @@ func TestFormatDocumentMarkdown(t *testing.T) {
 		if !strings.Contains(result, "Meeting ID: abc12345-1234-5678-9abc-def012345678") {
 			t.Error("Expected meeting ID in output")
 		}
-		if !strings.Contains(result, "## AI-Generated Notes") {
-			t.Error("Expected AI-Generated Notes section")
+		if !strings.Contains(result, "## My Notes") {
+			t.Error("Expected My Notes section")
 		}
 		if !strings.Contains(result, "Follow up on project timeline") {
 			t.Error("Expected notes content in output")
@@ -36,6 +37,54 @@ func TestFormatDocumentMarkdown(t *testing.T) {
 		}
 	})
 
+	t.Run("document with metadata", func(t *testing.T) {
+		doc := &Document{
+			ID:          "test-id",
+			Title:       "Acme Kickoff",
+			CreatedAt:   "2026-01-21T10:00:00Z",
+			UpdatedAt:   "2026-01-21T11:15:00Z",
+			WorkspaceID: "ws-1",
+			People: &People{Attendees: []Person{
+				{Name: "Bob Jones", Email: "bob@example.com"},
+				{Email: "carol@example.com"},
+			}},
+			GoogleCalendarEvent: &CalendarEvent{Summary: "Acme / Kickoff"},
+			Folders:             []string{"Acme", "Customers"},
+			NotesMarkdown:       "Some notes here",
+		}
+
+		result := FormatDocumentMarkdown(doc, nil)
+
+		for _, want := range []string{
+			"Updated: 2026-01-21 11:15",
+			"Attendees:\n- Bob Jones <bob@example.com>\n- carol@example.com",
+			"Calendar event: Acme / Kickoff",
+			"Folders: Acme, Customers",
+			"Workspace: ws-1",
+		} {
+			if !strings.Contains(result, want+"\n") {
+				t.Errorf("Expected %q in output", want)
+			}
+		}
+
+		header := ExtractHeaderFromMarkdown(result)
+		if header.MeetingID != "test-id" || header.Date != "2026-01-21 10:00" {
+			t.Errorf("Header no longer round-trips: %+v", header)
+		}
+	})
+
+	t.Run("omits missing metadata", func(t *testing.T) {
+		doc := &Document{ID: "test-id", Title: "Plain", NotesMarkdown: "Some notes here"}
+
+		result := FormatDocumentMarkdown(doc, nil)
+
+		for _, unwanted := range []string{"Updated:", "Attendees:", "Calendar event:", "Folders:", "Workspace:"} {
+			if strings.Contains(result, unwanted) {
+				t.Errorf("Did not expect %q in output", unwanted)
+			}
+		}
+	})
+
 	t.Run("document with transcript only", func(t *testing.T) {
 		doc := &Document{
 			ID:        "test-id",
@@ -76,14 +125,14 @@ func TestFormatDocumentMarkdown(t *testing.T) {
 
 		result := FormatDocumentMarkdown(doc, transcript)
 
-		if !strings.Contains(result, "## AI-Generated Notes") {
-			t.Error("Expected AI-Generated Notes section")
+		if !strings.Contains(result, "## My Notes") {
+			t.Error("Expected My Notes section")
 		}
 		if !strings.Contains(result, "## Transcript") {
 			t.Error("Expected Transcript section")
 		}
 		// Check that separator exists between sections
-		notesIdx := strings.Index(result, "## AI-Generated Notes")
+		notesIdx := strings.Index(result, "## My Notes")
 		transcriptIdx := strings.Index(result, "## Transcript")
 		separatorBetween := result[notesIdx:transcriptIdx]
 		if !strings.Contains(separatorBetween, "---") {
@@ -210,6 +259,244 @@ func TestFormatDocumentMarkdown(t *testing.T) {
 	})
 }
 
+func TestFormatDocumentMarkdownNotes(t *testing.T) {
+	doc := &Document{
+		ID:            "test-id",
+		Title:         "Notes",
+		CreatedAt:     "2026-01-21T10:00:00Z",
+		NotesMarkdown: "- typed during the call",
+		Panels:        []DocumentPanel{{Title: "Summary", Content: json.RawMessage(`"### Summary\n\nAI summary"`)}},
+	}
+	transcript := []TranscriptEntry{{Text: "Hello", Source: "microphone"}}
+
+	t.Run("renders my notes before AI notes", func(t *testing.T) {
+		result := FormatDocumentMarkdown(doc, transcript)
+
+		mine := strings.Index(result, "## My Notes")
+		ai := strings.Index(result, "## AI-Generated Notes")
+		tr := strings.Index(result, "## Transcript")
+		if mine < 0 || ai < 0 || tr < 0 || !(mine < ai && ai < tr) {
+			t.Errorf("Expected My Notes, AI-Generated Notes, Transcript in order:\n%s", result)
+		}
+		if !strings.Contains(result, "typed during the call") || !strings.Contains(result, "AI summary") {
+			t.Error("Expected both notes in output")
+		}
+		if strings.Count(result, "---") != 3 {
+			t.Errorf("Expected a separator between each section:\n%s", result)
+		}
+	})
+
+	t.Run("selects sections", func(t *testing.T) {
+		tests := []struct {
+			notes   string
+			want    string
+			notWant string
+		}{
+			{NotesMine, "## My Notes", "## AI-Generated Notes"},
+			{NotesAI, "## AI-Generated Notes", "## My Notes"},
+		}
+		for _, tt := range tests {
+			result := FormatDocumentMarkdownWithOptions(doc, nil, FormatOptions{Notes: tt.notes})
+			if !strings.Contains(result, tt.want) || strings.Contains(result, tt.notWant) {
+				t.Errorf("%s: expected %q without %q:\n%s", tt.notes, tt.want, tt.notWant, result)
+			}
+		}
+	})
+
+	t.Run("writes notes_markdown as my notes", func(t *testing.T) {
+		doc := &Document{ID: "test-id", NotesMarkdown: "Typed notes"}
+
+		result := FormatDocumentMarkdown(doc, nil)
+
+		if !strings.Contains(result, "## My Notes\n\nTyped notes") {
+			t.Errorf("Expected notes_markdown as my notes:\n%s", result)
+		}
+		if strings.Contains(result, "## AI-Generated Notes") {
+			t.Error("Did not expect AI notes without panels")
+		}
+	})
+}
+
+func TestFormatDocumentMarkdownCalendar(t *testing.T) {
+	doc := &Document{
+		ID:        "test-id",
+		Title:     `Acme "Kickoff"`,
+		CreatedAt: "2026-01-21T10:02:00Z",
+		GoogleCalendarEvent: &CalendarEvent{
+			Summary:     "Acme Kickoff",
+			Start:       &CalendarTime{DateTime: "2026-01-21T10:00:00Z"},
+			End:         &CalendarTime{DateTime: "2026-01-21T10:30:00Z"},
+			HangoutLink: "https://meet.google.com/abc-defg-hij",
+			Attendees: []CalendarAttendee{
+				{Email: "alice@example.com", DisplayName: "Alice Smith", Organizer: true},
+				{Email: "bob@example.com"},
+			},
+		},
+		Folders:       []string{"Customers"},
+		NotesMarkdown: "Some notes here",
+	}
+
+	t.Run("renders schedule, conference link and attendees in the header", func(t *testing.T) {
+		result := FormatDocumentMarkdown(doc, nil)
+
+		for _, want := range []string{
+			"Scheduled: 2026-01-21 10:00 - 10:30\n",
+			"Conference: https://meet.google.com/abc-defg-hij\n",
+			"Attendees:\n- Alice Smith <alice@example.com> (organizer)\n- bob@example.com\n\n---",
+		} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in output:\n%s", want, result)
+			}
+		}
+		if strings.HasPrefix(result, "---") {
+			t.Error("Did not expect front matter by default")
+		}
+	})
+
+	t.Run("renders front matter", func(t *testing.T) {
+		result := FormatDocumentMarkdownWithOptions(doc, nil, FormatOptions{FrontMatter: true})
+
+		want := strings.Join([]string{
+			"---",
+			`title: "Acme \"Kickoff\""`,
+			`meeting_id: "test-id"`,
+			`created: "2026-01-21T10:02:00Z"`,
+			`calendar_event: "Acme Kickoff"`,
+			`scheduled_start: "2026-01-21T10:00:00Z"`,
+			`scheduled_end: "2026-01-21T10:30:00Z"`,
+			`conference_url: "https://meet.google.com/abc-defg-hij"`,
+			"folders:",
+			`  - "Customers"`,
+			"attendees:",
+			`  - name: "Alice Smith"`,
+			`    email: "alice@example.com"`,
+			"    organizer: true",
+			`  - email: "bob@example.com"`,
+			"---",
+			"",
+			`# Acme "Kickoff"`,
+		}, "\n")
+		if !strings.HasPrefix(result, want) {
+			t.Errorf("Unexpected front matter:\n%s", result)
+		}
+
+		header := ExtractHeaderFromMarkdown(result)
+		if header.Title != `Acme "Kickoff"` || header.MeetingID != "test-id" {
+			t.Errorf("Header no longer round-trips with front matter: %+v", header)
+		}
+	})
+}
+
+func TestFormatDocumentMarkdownMergeTurns(t *testing.T) {
+	doc := &Document{ID: "test-id", Title: "Turns"}
+	transcript := []TranscriptEntry{
+		{Source: "system", Text: "Hello", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:01Z"},
+		{Source: "system", Text: "there.", StartTimestamp: "2026-01-21T10:00:02Z", EndTimestamp: "2026-01-21T10:00:03Z"},
+		{Source: "microphone", Text: "Hi!", StartTimestamp: "2026-01-21T10:00:04Z", EndTimestamp: "2026-01-21T10:00:05Z"},
+	}
+
+	result := FormatDocumentMarkdownWithOptions(doc, transcript, FormatOptions{MergeTurns: true, MergeGap: DefaultMergeGap})
+
+	if !strings.Contains(result, "**Them:** Hello there.\n\n**Me:** Hi!\n") {
+		t.Errorf("Expected merged turns:\n%s", result)
+	}
+
+	// The merged form reads back as one entry per turn and formats identically
+	extracted := ExtractTranscriptFromMarkdown(result)
+	if len(extracted) != 2 || extracted[0].Text != "Hello there." {
+		t.Errorf("Unexpected extracted transcript: %+v", extracted)
+	}
+	if again := FormatDocumentMarkdownWithOptions(doc, extracted, FormatOptions{MergeTurns: true}); again != result {
+		t.Errorf("Expected merged form to round-trip:\n%s", again)
+	}
+	if problem := checkTranscript(result); problem != "" {
+		t.Errorf("Expected merged transcript to verify, got %q", problem)
+	}
+}
+
+func TestFormatDocumentMarkdownStats(t *testing.T) {
+	doc := &Document{ID: "test-id", Title: "Stats", NotesMarkdown: "Some notes"}
+	transcript := []TranscriptEntry{
+		{Source: "system", Text: "Hello there", StartTimestamp: "2026-01-21T10:00:00Z", EndTimestamp: "2026-01-21T10:00:30Z"},
+		{Source: "microphone", Text: "Hi", StartTimestamp: "2026-01-21T10:00:30Z", EndTimestamp: "2026-01-21T10:01:00Z"},
+	}
+
+	t.Run("adds a stats section before the transcript", func(t *testing.T) {
+		result := FormatDocumentMarkdownWithOptions(doc, transcript, FormatOptions{Stats: true})
+
+		statsAt := strings.Index(result, "## Stats")
+		transcriptAt := strings.Index(result, "## Transcript")
+		if statsAt < 0 || statsAt > transcriptAt {
+			t.Fatalf("Expected stats before the transcript:\n%s", result)
+		}
+		for _, want := range []string{"Duration: 1m 0s", "Turns: 2", "| Them | 30s | 50% | 2 | 1 |", "| Me | 30s | 50% | 1 | 1 |"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in:\n%s", want, result)
+			}
+		}
+		if problem := checkTranscript(result); problem != "" {
+			t.Errorf("Expected transcript to verify, got %q", problem)
+		}
+		if extracted := ExtractTranscriptFromMarkdown(result); len(extracted) != 2 {
+			t.Errorf("Expected transcript to read back, got %+v", extracted)
+		}
+	})
+
+	t.Run("omits stats by default and without a transcript", func(t *testing.T) {
+		if result := FormatDocumentMarkdown(doc, transcript); strings.Contains(result, "## Stats") {
+			t.Errorf("Expected no stats section by default:\n%s", result)
+		}
+		if result := FormatDocumentMarkdownWithOptions(doc, nil, FormatOptions{Stats: true}); strings.Contains(result, "## Stats") {
+			t.Errorf("Expected no stats section without a transcript:\n%s", result)
+		}
+	})
+
+	t.Run("adds stats to front matter", func(t *testing.T) {
+		result := FormatDocumentMarkdownWithOptions(doc, transcript, FormatOptions{Stats: true, FrontMatter: true})
+
+		for _, want := range []string{"stats:\n  duration_seconds: 60\n  turns: 2\n  words: 3\n", "    - speaker: \"Them\"\n      talk_seconds: 30\n"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected %q in:\n%s", want, result)
+			}
+		}
+	})
+}
+
+func TestFormatSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end *CalendarTime
+		want       string
+	}{
+		{"same day", &CalendarTime{DateTime: "2026-01-21T10:00:00Z"}, &CalendarTime{DateTime: "2026-01-21T11:15:00Z"}, "2026-01-21 10:00 - 11:15"},
+		{"spans days", &CalendarTime{DateTime: "2026-01-21T23:00:00Z"}, &CalendarTime{DateTime: "2026-01-22T01:00:00Z"}, "2026-01-21 23:00 - 2026-01-22 01:00"},
+		{"keeps the event's offset", &CalendarTime{DateTime: "2026-01-21T09:00:00-05:00"}, &CalendarTime{DateTime: "2026-01-21T09:30:00-05:00"}, "2026-01-21 09:00 - 09:30"},
+		{"all day", &CalendarTime{Date: "2026-01-21"}, &CalendarTime{Date: "2026-01-22"}, "2026-01-21 - 2026-01-22"},
+		{"no end", &CalendarTime{DateTime: "2026-01-21T10:00:00Z"}, nil, "2026-01-21 10:00"},
+		{"no start", nil, &CalendarTime{DateTime: "2026-01-21T10:00:00Z"}, ""},
+		{"unparseable", &CalendarTime{DateTime: "soon"}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSchedule(tt.start, tt.end); got != tt.want {
+				t.Errorf("FormatSchedule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNotes(t *testing.T) {
+	for _, notes := range []string{"", NotesBoth, NotesAI, NotesMine} {
+		if err := ValidateNotes(notes); err != nil {
+			t.Errorf("ValidateNotes(%q) returned error: %v", notes, err)
+		}
+	}
+	if err := ValidateNotes("all"); err == nil {
+		t.Error("Expected error for unsupported notes selection")
+	}
+}
+
 func TestFormatDate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,101 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// formatFrontMatter renders a document's metadata, and its transcript stats
+// when given, as a YAML front matter block. Timestamps are written as Granola
+// stores them so tools can parse them without guessing a format.
+func formatFrontMatter(doc *Document, stats *TranscriptStats) []string {
+	lines := []string{"---"}
+	field := func(key, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", key, yamlString(value)))
+		}
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	field("title", title)
+	field("meeting_id", doc.ID)
+	field("created", doc.CreatedAt)
+	field("updated", doc.UpdatedAt)
+
+	if event := doc.GoogleCalendarEvent; event != nil {
+		field("calendar_event", event.Summary)
+		field("scheduled_start", calendarTimeValue(event.Start))
+		field("scheduled_end", calendarTimeValue(event.End))
+		field("conference_url", event.ConferenceURL())
+	}
+	field("workspace_id", doc.WorkspaceID)
+
+	if len(doc.Folders) > 0 {
+		lines = append(lines, "folders:")
+		for _, folder := range doc.Folders {
+			lines = append(lines, "  - "+yamlString(folder))
+		}
+	}
+
+	if attendees := doc.Attendees(); len(attendees) > 0 {
+		lines = append(lines, "attendees:")
+		for _, a := range attendees {
+			prefix := "  - "
+			if a.Name != "" {
+				lines = append(lines, prefix+"name: "+yamlString(a.Name))
+				prefix = "    "
+			}
+			if a.Email != "" {
+				lines = append(lines, prefix+"email: "+yamlString(a.Email))
+				prefix = "    "
+			}
+			if a.Organizer {
+				lines = append(lines, prefix+"organizer: true")
+			}
+		}
+	}
+
+	if stats != nil {
+		lines = append(lines, "stats:")
+		lines = append(lines, fmt.Sprintf("  duration_seconds: %d", stats.DurationSeconds))
+		lines = append(lines, fmt.Sprintf("  turns: %d", stats.Turns))
+		lines = append(lines, fmt.Sprintf("  words: %d", stats.Words))
+		lines = append(lines, "  speakers:")
+		for _, s := range stats.Speakers {
+			lines = append(lines, "    - speaker: "+yamlString(s.Speaker))
+			lines = append(lines, fmt.Sprintf("      talk_seconds: %d", s.TalkSeconds))
+			lines = append(lines, fmt.Sprintf("      words: %d", s.Words))
+			lines = append(lines, fmt.Sprintf("      turns: %d", s.Turns))
+		}
+		if m := stats.LongestMonologue; m != nil {
+			lines = append(lines, "  longest_monologue:")
+			lines = append(lines, "    speaker: "+yamlString(m.Speaker))
+			lines = append(lines, fmt.Sprintf("    duration_seconds: %d", m.DurationSeconds))
+			lines = append(lines, fmt.Sprintf("    words: %d", m.Words))
+		}
+	}
+
+	lines = append(lines, "---")
+	lines = append(lines, "")
+	return lines
+}
+
+// calendarTimeValue returns the raw timestamp, or date for all-day events.
+func calendarTimeValue(t *CalendarTime) string {
+	if t == nil {
+		return ""
+	}
+	if t.DateTime != "" {
+		return t.DateTime
+	}
+	return t.Date
+}
+
+// yamlString quotes s as a YAML double-quoted scalar. Go's escaping is a
+// subset of YAML's, so any string round-trips.
+func yamlString(s string) string {
+	return strconv.Quote(s)
+}
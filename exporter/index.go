@@ -0,0 +1,348 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IndexFilename is the overview page written to the top of the output
+// directory.
+const IndexFilename = "INDEX.md"
+
+// IndexMarker is the first line of every index page granary generates. It
+// tells them apart from meeting files, and from pages people wrote
+// themselves, which granary never overwrites or removes.
+const IndexMarker = "<!-- granary:index -->"
+
+// writeIndexPage writes an index page to path unless it already has exactly
+// that content. Refuses to replace a file that isn't a granary index page.
+// Reports whether the file was written.
+func writeIndexPage(path, content string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		if string(existing) == content {
+			return false, nil
+		}
+		if !strings.HasPrefix(string(existing), IndexMarker) {
+			return false, fmt.Errorf("refusing to replace %s, which granary didn't generate", filepath.Base(path))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("failed to create index directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write index: %w", err)
+	}
+	return true, nil
+}
+
+// isIndexPage reports whether the file at path is a granary index page.
+func isIndexPage(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	line, _ := bufio.NewReader(f).ReadString('\n')
+	return strings.TrimRight(line, "\r\n") == IndexMarker
+}
+
+// markdownLink formats a link to a file relative to dir. The destination is
+// wrapped in angle brackets because exported filenames contain spaces.
+func markdownLink(text, dir, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		rel = target
+	}
+	return fmt.Sprintf("[%s](<%s>)", text, filepath.ToSlash(rel))
+}
+
+// indexEntry is one meeting listed on the index page.
+type indexEntry struct {
+	title      string
+	date       time.Time
+	filename   string
+	notes      bool
+	transcript bool
+	words      int
+}
+
+// month is the page an entry is listed on when indexing by month.
+func (entry indexEntry) month() string {
+	if entry.date.IsZero() {
+		return "unknown-date"
+	}
+	return entry.date.Format("2006-01")
+}
+
+// writeIndex writes IndexFilename listing every meeting file in the output
+// directory, and a page per month when exporting by month. Index pages that
+// are no longer wanted are removed.
+func (e *Exporter) writeIndex(state *ExportState, docs []Document) error {
+	path := filepath.Join(e.OutputDir, IndexFilename)
+	logger := e.logger()
+
+	var entries []indexEntry
+	if e.Index {
+		var err error
+		if entries, err = e.indexEntries(state, docs); err != nil {
+			return err
+		}
+	}
+
+	wanted := make(map[string][]indexEntry)
+	if e.Index && e.ByMonth {
+		for _, entry := range entries {
+			wanted[entry.month()] = append(wanted[entry.month()], entry)
+		}
+	}
+	for month, group := range wanted {
+		rel := filepath.Join(month, IndexFilename)
+		written, err := writeIndexPage(filepath.Join(e.OutputDir, rel), e.formatMonthIndex(month, group))
+		if err != nil {
+			return err
+		}
+		if written {
+			logger.Info("wrote index", "file", rel, "meetings", len(group))
+		}
+	}
+
+	pages, err := filepath.Glob(filepath.Join(e.OutputDir, "*", IndexFilename))
+	if err != nil {
+		return err
+	}
+	for _, page := range pages {
+		month := filepath.Base(filepath.Dir(page))
+		if wanted[month] != nil || !isIndexPage(page) {
+			continue
+		}
+		if err := os.Remove(page); err != nil {
+			return fmt.Errorf("failed to remove index: %w", err)
+		}
+		logger.Info("removed index", "file", filepath.Join(month, IndexFilename))
+		removeEmptyDirs(e.OutputDir, filepath.Dir(page))
+	}
+
+	if !e.Index {
+		if !isIndexPage(path) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove index: %w", err)
+		}
+		logger.Info("removed index", "file", IndexFilename)
+		return nil
+	}
+
+	written, err := writeIndexPage(path, e.formatIndex(entries))
+	if err != nil {
+		return err
+	}
+	if written {
+		logger.Info("wrote index", "file", IndexFilename, "meetings", len(entries))
+	}
+	return nil
+}
+
+// indexEntries lists every meeting file in the output directory, including
+// files of documents no longer in the cache or that failed to export this
+// run. Files the export state describes, and that are unchanged since, are
+// listed from the state and the cache; only other files are read.
+func (e *Exporter) indexEntries(state *ExportState, docs []Document) ([]indexEntry, error) {
+	byID := make(map[string]*Document, len(docs))
+	for i := range docs {
+		byID[docs[i].ID] = &docs[i]
+	}
+	described := make(map[string]string, len(state.Documents))
+	for id, ds := range state.Documents {
+		described[filepath.Clean(ds.Filename)] = id
+	}
+
+	// A meeting with several files, such as one left behind by a failed
+	// move, is listed once, preferring the file the state describes
+	entries := make(map[string]indexEntry)
+	fromState := make(map[string]bool)
+	err := walkExports(e.OutputDir, func(path, rel string) error {
+		if id, ok := described[rel]; ok && byID[id] != nil {
+			ds := state.Documents[id]
+			if ds.matches(ds.Filename, ds.InputHash, path) {
+				entries[id] = e.stateIndexEntry(byID[id], ds)
+				fromState[id] = true
+				return nil
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		if strings.HasPrefix(string(content), IndexMarker) {
+			return nil
+		}
+		header := ExtractHeaderFromMarkdown(string(content))
+		id := header.MeetingID
+		if _, seen := entries[id]; id == "" || seen && (fromState[id] || described[rel] != id) {
+			return nil
+		}
+		entries[id] = fileIndexEntry(rel, header, string(content))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]indexEntry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	return list, nil
+}
+
+// stateIndexEntry describes a document's file from its export state.
+func (e *Exporter) stateIndexEntry(doc *Document, ds DocumentState) indexEntry {
+	mine, ai := e.Format.notes(doc)
+	entry := indexEntry{
+		title:      doc.Title,
+		filename:   ds.Filename,
+		notes:      mine != "" || ai != "",
+		transcript: ds.Transcript,
+		words:      ds.Words,
+	}
+	entry.date, _ = parseTimestamp(doc.CreatedAt)
+	return entry
+}
+
+// fileIndexEntry describes a meeting file from its contents.
+func fileIndexEntry(rel string, header ExportHeader, content string) indexEntry {
+	mine, ai := ExtractNotesFromMarkdown(content)
+	entry := indexEntry{
+		title:      header.Title,
+		filename:   rel,
+		notes:      mine != "" || ai != "",
+		transcript: strings.Contains(content, "## Transcript"),
+		words:      len(strings.Fields(content)),
+	}
+	entry.date, _ = time.Parse("2006-01-02 15:04", header.Date)
+	return entry
+}
+
+// sortIndexEntries orders entries newest first.
+func sortIndexEntries(entries []indexEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].date.Equal(entries[j].date) {
+			return entries[i].date.After(entries[j].date)
+		}
+		return entries[i].filename < entries[j].filename
+	})
+}
+
+// formatIndex renders the top-level index page, newest first: a table of
+// meetings per month, or a list of the month pages when exporting by month.
+func (e *Exporter) formatIndex(entries []indexEntry) string {
+	sortIndexEntries(entries)
+
+	lines := []string{IndexMarker, "# Meetings", ""}
+	lines = append(lines, fmt.Sprintf("%s meetings exported.", NumberWithCommas(len(entries))))
+
+	if e.ByMonth {
+		lines = append(lines, "")
+		lines = append(lines, "| Month | Meetings |")
+		lines = append(lines, "| --- | --- |")
+		for i := 0; i < len(entries); {
+			month := entries[i].month()
+			n := 0
+			for i < len(entries) && entries[i].month() == month {
+				i++
+				n++
+			}
+			link := markdownLink(monthHeading(entries[i-1]), e.OutputDir, filepath.Join(e.OutputDir, month, IndexFilename))
+			lines = append(lines, fmt.Sprintf("| %s | %s |", link, NumberWithCommas(n)))
+		}
+		lines = append(lines, "")
+		return strings.Join(lines, "\n")
+	}
+
+	heading := ""
+	for _, entry := range entries {
+		if monthHeading(entry) != heading {
+			heading = monthHeading(entry)
+			lines = append(lines, "")
+			lines = append(lines, "## "+heading)
+			lines = append(lines, "")
+			lines = append(lines, indexTableHeader...)
+		}
+		lines = append(lines, e.indexRow(e.OutputDir, entry))
+	}
+	lines = append(lines, "")
+
+	return strings.Join(lines, "\n")
+}
+
+// formatMonthIndex renders the index page of one month's meetings, newest
+// first.
+func (e *Exporter) formatMonthIndex(month string, entries []indexEntry) string {
+	sortIndexEntries(entries)
+	dir := filepath.Join(e.OutputDir, month)
+
+	lines := []string{IndexMarker, "# " + monthHeading(entries[0]), ""}
+	lines = append(lines, fmt.Sprintf("%s meetings.", NumberWithCommas(len(entries))))
+	lines = append(lines, "")
+	lines = append(lines, indexTableHeader...)
+	for _, entry := range entries {
+		lines = append(lines, e.indexRow(dir, entry))
+	}
+	lines = append(lines, "")
+
+	return strings.Join(lines, "\n")
+}
+
+// indexTableHeader starts the table of meetings on an index page.
+var indexTableHeader = []string{
+	"| Date | Meeting | Notes | Transcript | Words |",
+	"| --- | --- | --- | --- | --- |",
+}
+
+// monthHeading names the month an entry is listed under.
+func monthHeading(entry indexEntry) string {
+	if entry.date.IsZero() {
+		return "Unknown date"
+	}
+	return entry.date.Format("January 2006")
+}
+
+// indexRow renders an entry as a table row linking to its file from dir.
+func (e *Exporter) indexRow(dir string, entry indexEntry) string {
+	date := "Unknown date"
+	if !entry.date.IsZero() {
+		date = entry.date.Format("2006-01-02 15:04")
+	}
+	title := entry.title
+	if title == "" {
+		title = "Untitled"
+	}
+	link := markdownLink(escapeTableText(title), dir, filepath.Join(e.OutputDir, entry.filename))
+	return fmt.Sprintf("| %s | %s | %s | %s | %s |",
+		date, link, yesOrNo(entry.notes), yesOrNo(entry.transcript), NumberWithCommas(entry.words))
+}
+
+// escapeTableText escapes characters that would break a markdown table cell
+// or link text.
+func escapeTableText(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "|", "\\|", "[", "\\[", "]", "\\]").Replace(s)
+}
+
+// yesOrNo formats a flag for a table cell.
+func yesOrNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
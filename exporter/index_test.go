@@ -0,0 +1,232 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExporterIndex(t *testing.T) {
+	newState := func() *CacheState {
+		return &CacheState{
+			Documents: map[string]Document{
+				"jan": {ID: "jan", Title: "Kickoff | Acme", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Some notes here"},
+				"feb": {ID: "feb", Title: "Review", CreatedAt: "2026-02-03T09:30:00Z"},
+			},
+			Transcripts: map[string][]TranscriptEntry{
+				"feb": {{Source: "microphone", Text: "Hello everyone"}},
+			},
+		}
+	}
+
+	t.Run("lists meetings by month, newest first", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, IndexFilename))
+		if err != nil {
+			t.Fatalf("Expected index: %v", err)
+		}
+		got := string(content)
+		if !strings.HasPrefix(got, IndexMarker+"\n# Meetings\n\n2 meetings exported.\n") {
+			t.Errorf("Unexpected index header:\n%s", got)
+		}
+		feb := strings.Index(got, "## February 2026")
+		jan := strings.Index(got, "## January 2026")
+		if feb < 0 || jan < feb {
+			t.Errorf("Expected months newest first:\n%s", got)
+		}
+		for _, want := range []string{
+			"| 2026-02-03 09:30 | [Review](<2026-02-03_Review.md>) | no | yes | ",
+			`| 2026-01-21 10:00 | [Kickoff \| Acme](<2026-01-21_Kickoff  Acme.md>) | yes | no | `,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Expected %q in:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("lists files of documents no longer in the cache", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+
+		evicted := newState()
+		delete(evicted.Documents, "jan")
+		if _, err := exp.Export(evicted); err != nil {
+			t.Fatal(err)
+		}
+
+		content, _ := os.ReadFile(filepath.Join(tmpDir, IndexFilename))
+		got := string(content)
+		if !strings.Contains(got, "2 meetings exported.") || !strings.Contains(got, `[Kickoff \| Acme](<2026-01-21_Kickoff  Acme.md>) | yes | no | `) {
+			t.Errorf("Expected the evicted meeting to stay listed:\n%s", got)
+		}
+	})
+
+	t.Run("lists unchanged files from the state without reading them", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		indexPath := filepath.Join(tmpDir, IndexFilename)
+		before, _ := os.ReadFile(indexPath)
+
+		// A same-size edit keeps the mtime and size the state recorded, so
+		// reading the file is the only way to see the title change
+		path := filepath.Join(tmpDir, "2026-01-21_Kickoff  Acme.md")
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := os.ReadFile(path)
+		if err := os.WriteFile(path, []byte(strings.Replace(string(content), "# Kickoff | Acme", "# Kickoff | Acmf", 1)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		if after, _ := os.ReadFile(indexPath); string(after) != string(before) {
+			t.Errorf("Expected the index to come from the state, got:\n%s", after)
+		}
+	})
+
+	t.Run("writes a page per month by month", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+		exp.ByMonth = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(tmpDir, "2026-02", "2026-02-03_Review.md")); err != nil {
+			t.Errorf("Expected the meeting in its month directory: %v", err)
+		}
+		content, _ := os.ReadFile(filepath.Join(tmpDir, IndexFilename))
+		top := string(content)
+		for _, want := range []string{
+			"| [February 2026](<2026-02/INDEX.md>) | 1 |\n| [January 2026](<2026-01/INDEX.md>) | 1 |",
+		} {
+			if !strings.Contains(top, want) {
+				t.Errorf("Expected %q in:\n%s", want, top)
+			}
+		}
+		if strings.Contains(top, "Review") {
+			t.Errorf("Expected meetings only on the month pages:\n%s", top)
+		}
+
+		content, _ = os.ReadFile(filepath.Join(tmpDir, "2026-02", IndexFilename))
+		month := string(content)
+		if !strings.HasPrefix(month, IndexMarker+"\n# February 2026\n\n1 meetings.\n") ||
+			!strings.Contains(month, "| 2026-02-03 09:30 | [Review](<2026-02-03_Review.md>) | no | yes | ") {
+			t.Errorf("Unexpected month index:\n%s", month)
+		}
+
+		exp.ByMonth = false
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "2026-02")); !os.IsNotExist(err) {
+			t.Errorf("Expected the month directory to be removed, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "2026-02-03_Review.md")); err != nil {
+			t.Errorf("Expected the meeting moved back: %v", err)
+		}
+	})
+
+	t.Run("skips writing an unchanged index", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(tmpDir, IndexFilename)
+		before, _ := os.Stat(path)
+
+		// Word counts come from the state when documents are skipped
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		after, _ := os.Stat(path)
+		if !after.ModTime().Equal(before.ModTime()) {
+			t.Error("Expected unchanged index not to be rewritten")
+		}
+	})
+
+	t.Run("is not treated as a meeting file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Verify(tmpDir, newState().AllDocuments())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Files != 2 || len(result.Problems) != 0 {
+			t.Errorf("Expected only meeting files to be verified, got %+v", result)
+		}
+	})
+
+	t.Run("is removed when turned off", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.Index = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+
+		exp.Index = false
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, IndexFilename)); !os.IsNotExist(err) {
+			t.Errorf("Expected index to be removed, got %v", err)
+		}
+	})
+
+	t.Run("leaves a hand-written INDEX.md alone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		path := filepath.Join(tmpDir, IndexFilename)
+		writeTestFile(t, path, "# My index\n")
+
+		exp := NewExporter(tmpDir)
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if content, _ := os.ReadFile(path); string(content) != "# My index\n" {
+			t.Errorf("Expected hand-written index to be kept, got %q", content)
+		}
+
+		exp.Index = true
+		result, err := exp.Export(newState())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.IndexErrors) != 1 || !strings.Contains(result.IndexErrors[0], "refusing to replace INDEX.md") {
+			t.Errorf("Expected an index error instead of overwriting a hand-written index, got %v", result.IndexErrors)
+		}
+		if result.Written != 0 || result.Skipped != 2 {
+			t.Errorf("Expected documents to still be exported, got %+v", result)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// ErrCacheIncomplete reports that the cache file could not be parsed because
+// it was truncated or only partly written, usually because Granola was
+// writing it at the time.
+var ErrCacheIncomplete = errors.New("cache file is incomplete, Granola may still be writing it")
+
+// CacheFormatError reports a cache file that is valid JSON but not in a shape
+// granary understands, which usually means Granola changed its cache format.
+type CacheFormatError struct {
+	Path string
+	Err  error
+}
+
+func (e *CacheFormatError) Error() string {
+	return fmt.Sprintf("unrecognized cache format in %s (Granola may have changed it): %v", e.Path, e.Err)
+}
+
+func (e *CacheFormatError) Unwrap() error {
+	return e.Err
+}
+
+// Default retry settings for LoadCacheWithRetry.
+const (
+	DefaultLoadAttempts = 4
+	DefaultLoadBackoff  = 500 * time.Millisecond
+)
+
+// LoadOptions configures LoadCacheWithRetry.
+type LoadOptions struct {
+	// Attempts is the total number of tries for transient failures.
+	Attempts int
+	// Backoff is the wait before the first retry; it doubles on each retry.
+	Backoff time.Duration
+	// Snapshot copies the cache to a temporary file before parsing, so
+	// Granola can keep writing the original while it is read.
+	Snapshot bool
+	// Logger receives retry messages. A nil Logger discards them.
+	Logger *slog.Logger
+}
+
+// LoadCacheWithRetry loads the cache like LoadCache, retrying with
+// exponential backoff while the file looks partly written. Errors wrap
+// ErrCacheIncomplete when retries ran out, or are a *CacheFormatError when
+// the file parsed as JSON but had an unexpected shape.
+func LoadCacheWithRetry(path string, opts LoadOptions) (*CacheState, error) {
+	if opts.Attempts <= 0 {
+		opts.Attempts = DefaultLoadAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultLoadBackoff
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	backoff := opts.Backoff
+	var err error
+	for attempt := 1; attempt <= opts.Attempts; attempt++ {
+		var state *CacheState
+		state, err = loadCacheOnce(path, opts.Snapshot)
+		if err == nil {
+			return state, nil
+		}
+		if !isTransientCacheError(err) {
+			if isFormatError(err) {
+				return nil, &CacheFormatError{Path: path, Err: err}
+			}
+			return nil, err
+		}
+
+		if attempt < opts.Attempts {
+			logger.Warn("cache not readable yet, retrying", "attempt", attempt, "wait", backoff, "error", err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	return nil, fmt.Errorf("%w (gave up after %d attempts): %w", ErrCacheIncomplete, opts.Attempts, err)
+}
+
+// loadCacheOnce parses the cache at path, optionally from a snapshot copy.
+func loadCacheOnce(path string, snapshot bool) (*CacheState, error) {
+	if !snapshot {
+		return LoadCache(path)
+	}
+
+	copyPath, err := snapshotFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(copyPath)
+
+	return LoadCache(copyPath)
+}
+
+// snapshotFile copies path to a temporary file and returns its path.
+// Fails with ErrCacheIncomplete if the file changed while being copied.
+func snapshotFile(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cache file: %w", err)
+	}
+	defer src.Close()
+
+	before, err := src.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat cache file: %w", err)
+	}
+
+	dst, err := os.CreateTemp("", "granary-cache-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache snapshot: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to copy cache snapshot: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to copy cache snapshot: %w", err)
+	}
+
+	after, err := os.Stat(path)
+	if err != nil || after.Size() != before.Size() || !after.ModTime().Equal(before.ModTime()) {
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("cache changed while taking snapshot: %w", ErrCacheIncomplete)
+	}
+
+	return dst.Name(), nil
+}
+
+// isTransientCacheError reports whether err looks like a read of a
+// partly-written cache that may succeed if retried.
+func isTransientCacheError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, ErrCacheIncomplete) ||
+		errors.Is(err, os.ErrNotExist) ||
+		errors.As(err, &syntaxErr)
+}
+
+// isFormatError reports whether err came from well-formed JSON that didn't
+// match the expected cache structure.
+func isFormatError(err error) bool {
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &typeErr) || errors.Is(err, errUnexpectedShape) || errors.Is(err, errCacheFieldEmpty)
+}
@@ -0,0 +1,102 @@
+package exporter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadCacheWithRetry(t *testing.T) {
+	complete := syntheticCache(3, false)
+
+	t.Run("loads a complete cache", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache-v6.json")
+		writeTestFile(t, path, string(complete))
+
+		state, err := LoadCacheWithRetry(path, LoadOptions{Attempts: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(state.Documents) != 3 {
+			t.Errorf("Expected 3 documents, got %d", len(state.Documents))
+		}
+	})
+
+	t.Run("retries until a partial write completes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache-v6.json")
+		writeTestFile(t, path, string(complete[:len(complete)/3]))
+
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			os.WriteFile(path, complete, 0644)
+		}()
+
+		state, err := LoadCacheWithRetry(path, LoadOptions{Attempts: 6, Backoff: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(state.Documents) != 3 {
+			t.Errorf("Expected 3 documents, got %d", len(state.Documents))
+		}
+	})
+
+	t.Run("reports incomplete cache after retries run out", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache-v6.json")
+		writeTestFile(t, path, string(complete[:len(complete)/2]))
+
+		_, err := LoadCacheWithRetry(path, LoadOptions{Attempts: 2, Backoff: time.Millisecond})
+		if !errors.Is(err, ErrCacheIncomplete) {
+			t.Fatalf("Expected ErrCacheIncomplete, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "gave up after 2 attempts") {
+			t.Errorf("Expected attempt count in error, got %v", err)
+		}
+	})
+
+	t.Run("reports changed format without retrying", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache-v7.json")
+		writeTestFile(t, path, `{"cache":{"state":{"documents":["not","a","map"]}}}`)
+
+		start := time.Now()
+		_, err := LoadCacheWithRetry(path, LoadOptions{Attempts: 3, Backoff: time.Second})
+		var formatErr *CacheFormatError
+		if !errors.As(err, &formatErr) {
+			t.Fatalf("Expected CacheFormatError, got %v", err)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Error("Expected format errors to fail without retrying")
+		}
+	})
+
+	t.Run("reports missing cache field as a format error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache-v7.json")
+		writeTestFile(t, path, `{"data":{}}`)
+
+		_, err := LoadCacheWithRetry(path, LoadOptions{Attempts: 1})
+		var formatErr *CacheFormatError
+		if !errors.As(err, &formatErr) {
+			t.Fatalf("Expected CacheFormatError, got %v", err)
+		}
+	})
+
+	t.Run("parses from a snapshot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache-v6.json")
+		writeTestFile(t, path, string(complete))
+
+		before, _ := filepath.Glob(filepath.Join(os.TempDir(), "granary-cache-*.json"))
+		state, err := LoadCacheWithRetry(path, LoadOptions{Attempts: 1, Snapshot: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(state.Documents) != 3 {
+			t.Errorf("Expected 3 documents, got %d", len(state.Documents))
+		}
+		after, _ := filepath.Glob(filepath.Join(os.TempDir(), "granary-cache-*.json"))
+		if len(after) != len(before) {
+			t.Error("Expected snapshot to be removed")
+		}
+	})
+}
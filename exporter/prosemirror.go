@@ -0,0 +1,285 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// proseMirrorNode is a node in Granola's structured note content, which
+// follows the ProseMirror document model.
+type proseMirrorNode struct {
+	Type    string            `json:"type"`
+	Attrs   map[string]any    `json:"attrs,omitempty"`
+	Content []proseMirrorNode `json:"content,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	Marks   []proseMirrorMark `json:"marks,omitempty"`
+}
+
+// proseMirrorMark is inline formatting applied to a text node.
+type proseMirrorMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// ProseMirrorToMarkdown converts a ProseMirror JSON document to markdown.
+// Supports headings, paragraphs, bullet and ordered lists (nested to any
+// depth), blockquotes, code blocks, horizontal rules, hard breaks, mentions,
+// and bold, italic, strikethrough, code and link marks. Unknown nodes are
+// rendered through their children so no text is lost.
+func ProseMirrorToMarkdown(data []byte) (string, error) {
+	var root proseMirrorNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return "", fmt.Errorf("failed to parse note content: %w", err)
+	}
+
+	blocks := renderBlocks(root.Content)
+	if root.Type != "doc" && root.Type != "" {
+		blocks = renderBlocks([]proseMirrorNode{root})
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// renderBlocks renders block nodes, dropping any that render empty.
+func renderBlocks(nodes []proseMirrorNode) []string {
+	var blocks []string
+	for _, node := range nodes {
+		if block := renderBlock(node); strings.TrimSpace(block) != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// renderBlock renders a single block node.
+func renderBlock(node proseMirrorNode) string {
+	switch node.Type {
+	case "paragraph":
+		return renderInline(node.Content)
+	case "heading":
+		level := attrInt(node.Attrs, "level", 1)
+		level = min(max(level, 1), 6)
+		return strings.Repeat("#", level) + " " + renderInline(node.Content)
+	case "bulletList", "bullet_list":
+		return renderList(node, false)
+	case "orderedList", "ordered_list":
+		return renderList(node, true)
+	case "blockquote":
+		body := strings.Join(renderBlocks(node.Content), "\n\n")
+		return prefixLines(body, "> ", ">")
+	case "codeBlock", "code_block":
+		lang, _ := node.Attrs["language"].(string)
+		return "```" + lang + "\n" + plainText(node.Content) + "\n```"
+	case "horizontalRule", "horizontal_rule":
+		// Not "---", which granary uses to separate the sections of a file
+		return "***"
+	case "text", "hardBreak", "hard_break", "mention":
+		return renderInline([]proseMirrorNode{node})
+	default:
+		if len(node.Content) > 0 && isInline(node.Content[0]) {
+			return renderInline(node.Content)
+		}
+		return strings.Join(renderBlocks(node.Content), "\n\n")
+	}
+}
+
+// renderList renders a bullet or ordered list. Nested lists are indented to
+// line up with the text of their parent item.
+func renderList(node proseMirrorNode, ordered bool) string {
+	number := attrInt(node.Attrs, "start", 1)
+
+	var items []string
+	for _, item := range node.Content {
+		marker := "-"
+		if ordered {
+			marker = strconv.Itoa(number) + "."
+			number++
+		}
+		items = append(items, renderListItem(item, marker))
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderListItem renders one list item with its marker. The first block
+// follows the marker; later blocks are indented to line up with it. Nested
+// lists follow the line before, while further paragraphs are separated by a
+// blank line so they stay part of the item.
+func renderListItem(item proseMirrorNode, marker string) string {
+	children := item.Content
+	if item.Type != "listItem" && item.Type != "list_item" {
+		children = []proseMirrorNode{item}
+	}
+
+	indent := strings.Repeat(" ", len(marker)+1)
+	var b strings.Builder
+	for _, child := range children {
+		block := renderBlock(child)
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		switch {
+		case b.Len() == 0:
+			b.WriteString(marker + " " + indentLines(block, indent))
+		case isList(child):
+			b.WriteString("\n" + indent + indentLines(block, indent))
+		default:
+			b.WriteString("\n\n" + indent + indentLines(block, indent))
+		}
+	}
+	if b.Len() == 0 {
+		return marker
+	}
+	return b.String()
+}
+
+// renderInline renders inline nodes, applying their marks.
+func renderInline(nodes []proseMirrorNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			b.WriteString(applyMarks(node.Text, node.Marks))
+		case "hardBreak", "hard_break":
+			b.WriteString("  \n")
+		case "mention":
+			label := attrString(node.Attrs, "label", "name", "id")
+			if label != "" {
+				b.WriteString("@" + label)
+			}
+		default:
+			b.WriteString(renderInline(node.Content))
+		}
+	}
+	return b.String()
+}
+
+// applyMarks wraps text in the markdown for its marks. Code is applied
+// innermost and links outermost.
+func applyMarks(text string, marks []proseMirrorMark) string {
+	if text == "" {
+		return ""
+	}
+
+	var href string
+	var bold, italic, strike, code bool
+	for _, mark := range marks {
+		switch mark.Type {
+		case "bold", "strong":
+			bold = true
+		case "italic", "em":
+			italic = true
+		case "strike", "strikethrough":
+			strike = true
+		case "code":
+			code = true
+		case "link":
+			href = attrString(mark.Attrs, "href")
+		}
+	}
+
+	// Keep surrounding whitespace outside the markers so they stay valid
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+
+	out := trimmed
+	if code {
+		out = "`" + out + "`"
+	}
+	if strike {
+		out = "~~" + out + "~~"
+	}
+	if italic {
+		out = "*" + out + "*"
+	}
+	if bold {
+		out = "**" + out + "**"
+	}
+	if href != "" {
+		out = "[" + out + "](" + href + ")"
+	}
+	return lead + out + trail
+}
+
+// plainText concatenates the text of nodes without any formatting.
+func plainText(nodes []proseMirrorNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		if node.Type == "hardBreak" || node.Type == "hard_break" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(node.Text)
+		b.WriteString(plainText(node.Content))
+	}
+	return b.String()
+}
+
+// isInline reports whether node is an inline node.
+func isInline(node proseMirrorNode) bool {
+	switch node.Type {
+	case "text", "hardBreak", "hard_break", "mention":
+		return true
+	}
+	return false
+}
+
+// isList reports whether node is a bullet or ordered list.
+func isList(node proseMirrorNode) bool {
+	switch node.Type {
+	case "bulletList", "bullet_list", "orderedList", "ordered_list":
+		return true
+	}
+	return false
+}
+
+// indentLines indents every non-empty line of s after the first.
+func indentLines(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// prefixLines prefixes every line of s, using blankPrefix for empty lines.
+func prefixLines(s, prefix, blankPrefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blankPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attrInt returns a numeric attribute, or def if it is missing.
+func attrInt(attrs map[string]any, key string, def int) int {
+	switch v := attrs[key].(type) {
+	case float64:
+		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// attrString returns the first non-empty string attribute among keys.
+func attrString(attrs map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := attrs[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProseMirrorToMarkdown(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "prosemirror", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("No ProseMirror fixtures found")
+	}
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".json")
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(strings.TrimSuffix(fixture, ".json") + ".md")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ProseMirrorToMarkdown(input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != strings.TrimRight(string(want), "\n") {
+				t.Errorf("Unexpected markdown:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+			}
+		})
+	}
+
+	t.Run("rejects invalid content", func(t *testing.T) {
+		if _, err := ProseMirrorToMarkdown([]byte(`"just a string"`)); err == nil {
+			t.Error("Expected error for non-object content")
+		}
+	})
+}
+
+func TestGetNotesProseMirrorFallback(t *testing.T) {
+	notes := []byte(`{"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Only in structured notes"}]}]}`)
+
+	t.Run("uses structured notes when text fields are empty", func(t *testing.T) {
+		doc := &Document{ID: "doc1", Notes: notes}
+
+		if got := doc.GetNotes(); got != "Only in structured notes" {
+			t.Errorf("Unexpected notes: %q", got)
+		}
+		if !doc.HasExportableContent(nil) {
+			t.Error("Expected document with structured notes to be exportable")
+		}
+	})
+
+	t.Run("prefers notes_markdown", func(t *testing.T) {
+		doc := &Document{ID: "doc1", NotesMarkdown: "Markdown notes", Notes: notes}
+
+		if got := doc.GetNotes(); got != "Markdown notes" {
+			t.Errorf("Unexpected notes: %q", got)
+		}
+	})
+
+	t.Run("ignores unparseable structured notes", func(t *testing.T) {
+		doc := &Document{ID: "doc1", Notes: []byte(`42`)}
+
+		if got := doc.GetNotes(); got != "" {
+			t.Errorf("Expected no notes, got %q", got)
+		}
+	})
+
+	t.Run("decodes structured notes from the cache", func(t *testing.T) {
+		data := []byte(`{"cache": {"state": {"documents": {"doc1": {"id": "doc1", "notes_markdown": "", "notes": {"type": "doc", "content": [{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Agenda"}]}]}}}}}}`)
+
+		state, err := ParseCache(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		doc := state.Documents["doc1"]
+		if got := doc.GetNotes(); got != "## Agenda" {
+			t.Errorf("Unexpected notes: %q", got)
+		}
+	})
+}
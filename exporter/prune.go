@@ -0,0 +1,178 @@
+package exporter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PruneOptions controls how orphaned files are removed.
+type PruneOptions struct {
+	// Delete removes files instead of moving them to the archive folder.
+	Delete bool
+	// Force also prunes files that contain a preserved transcript.
+	Force bool
+}
+
+// PruneResult lists what Prune did with each orphaned file, by relative path.
+type PruneResult struct {
+	Archived  []string
+	Deleted   []string
+	Protected []string
+	// Unlinked are the folder links that pointed at pruned files.
+	Unlinked []string
+}
+
+// FindOrphans returns exported files whose Meeting ID no longer matches a
+// document in the cache. Files without a Meeting ID header are ignored.
+func FindOrphans(files []ExportedFile, docs map[string]Document) []ExportedFile {
+	var orphans []ExportedFile
+	for _, f := range files {
+		if f.Header.MeetingID == "" {
+			continue
+		}
+		if _, ok := docs[f.Header.MeetingID]; !ok {
+			orphans = append(orphans, f)
+		}
+	}
+	return orphans
+}
+
+// Prune archives or deletes orphaned files from the output directory.
+// Files containing a transcript are left in place unless opts.Force is set,
+// since the transcript may no longer exist anywhere else. Folder links to a
+// pruned file are removed with it, and the file is dropped from the export
+// state.
+func Prune(outputDir string, orphans []ExportedFile, opts PruneOptions) (*PruneResult, error) {
+	result := &PruneResult{}
+
+	links, err := folderLinks(outputDir)
+	if err != nil {
+		return result, err
+	}
+
+	var pruned []ExportedFile
+	for _, f := range orphans {
+		if f.HasTranscript && !opts.Force {
+			result.Protected = append(result.Protected, f.RelPath)
+			continue
+		}
+		if err = pruneFile(outputDir, f, links[filepath.Clean(f.Path)], opts, result); err != nil {
+			break
+		}
+		pruned = append(pruned, f)
+	}
+
+	// Update the state even if a file failed, so it never lists files that
+	// are gone
+	if stateErr := forgetPruned(outputDir, pruned); err == nil {
+		err = stateErr
+	}
+	return result, err
+}
+
+// pruneFile archives or deletes one orphaned file and removes links to it.
+func pruneFile(outputDir string, f ExportedFile, links []string, opts PruneOptions, result *PruneResult) error {
+	if opts.Delete {
+		if err := os.Remove(f.Path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", f.RelPath, err)
+		}
+		result.Deleted = append(result.Deleted, f.RelPath)
+	} else {
+		dest := archivePath(filepath.Join(outputDir, ArchiveDirName, f.RelPath))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("failed to create archive directory: %w", err)
+		}
+		if err := os.Rename(f.Path, dest); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", f.RelPath, err)
+		}
+		result.Archived = append(result.Archived, f.RelPath)
+	}
+
+	for _, link := range links {
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("failed to remove link to %s: %w", f.RelPath, err)
+		}
+		rel, _ := filepath.Rel(outputDir, link)
+		result.Unlinked = append(result.Unlinked, rel)
+		removeEmptyDirs(outputDir, filepath.Dir(link))
+	}
+	removeEmptyDirs(outputDir, filepath.Dir(f.Path))
+	return nil
+}
+
+// archivePath returns dest, or when a file was already archived there, the
+// first numbered name next to it that is free, such as "Kickoff (2).md".
+// Renaming over an archived file would lose it.
+func archivePath(dest string) string {
+	base := strings.TrimSuffix(dest, ".md")
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(dest); os.IsNotExist(err) {
+			return dest
+		}
+		dest = fmt.Sprintf("%s (%d).md", base, n)
+	}
+}
+
+// folderLinks maps each file that folder links in outputDir point at to the
+// paths of those links. The same folders ScanExports skips are skipped here.
+func folderLinks(outputDir string) (map[string][]string, error) {
+	links := make(map[string][]string)
+	err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != outputDir && (strings.HasPrefix(d.Name(), ".") || d.Name() == ArchiveDirName || d.Name() == SeriesDirName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink == 0 || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		target = filepath.Clean(target)
+		links[target] = append(links[target], path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan output directory: %w", err)
+	}
+	return links, nil
+}
+
+// forgetPruned removes pruned files from the export state, so the next
+// export doesn't look for them or their links.
+func forgetPruned(outputDir string, pruned []ExportedFile) error {
+	if len(pruned) == 0 {
+		return nil
+	}
+	state, err := LoadExportState(outputDir)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, f := range pruned {
+		for id, ds := range state.Documents {
+			if id == f.Header.MeetingID || ds.Filename == f.RelPath {
+				delete(state.Documents, id)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return state.Save(outputDir)
+}
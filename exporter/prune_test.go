@@ -0,0 +1,135 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindOrphans(t *testing.T) {
+	files := []ExportedFile{
+		{RelPath: "a.md", Header: ExportHeader{MeetingID: "doc1"}},
+		{RelPath: "b.md", Header: ExportHeader{MeetingID: "deleted"}},
+		{RelPath: "c.md"},
+	}
+	docs := map[string]Document{"doc1": {ID: "doc1"}}
+
+	orphans := FindOrphans(files, docs)
+
+	if len(orphans) != 1 || orphans[0].RelPath != "b.md" {
+		t.Errorf("Expected only b.md to be orphaned, got %+v", orphans)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	setup := func(t *testing.T) (string, []ExportedFile) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, "notes.md"), "# Notes\nMeeting ID: gone1\n")
+		writeTestFile(t, filepath.Join(tmpDir, "talk.md"), "# Talk\nMeeting ID: gone2\n\n---\n\n## Transcript\n\n**Me:** Hi\n")
+		files, err := ScanExports(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tmpDir, files
+	}
+
+	t.Run("archives orphans and keeps transcripts", func(t *testing.T) {
+		tmpDir, files := setup(t)
+
+		result, err := Prune(tmpDir, files, PruneOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.Archived) != 1 || result.Archived[0] != "notes.md" {
+			t.Errorf("Expected notes.md archived, got %v", result.Archived)
+		}
+		if len(result.Protected) != 1 || result.Protected[0] != "talk.md" {
+			t.Errorf("Expected talk.md protected, got %v", result.Protected)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, ArchiveDirName, "notes.md")); err != nil {
+			t.Error("Expected notes.md in archive folder")
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "talk.md")); err != nil {
+			t.Error("Expected talk.md to stay in place")
+		}
+	})
+
+	t.Run("never replaces a file archived earlier", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		for _, body := range []string{"first", "second", "third"} {
+			writeTestFile(t, filepath.Join(tmpDir, "notes.md"), "# Notes\nMeeting ID: gone1\n\n"+body+"\n")
+			files, err := ScanExports(tmpDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Prune(tmpDir, files, PruneOptions{}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		for name, body := range map[string]string{"notes.md": "first", "notes (2).md": "second", "notes (3).md": "third"} {
+			content, err := os.ReadFile(filepath.Join(tmpDir, ArchiveDirName, name))
+			if err != nil || !strings.Contains(string(content), body) {
+				t.Errorf("Expected %s archived as %s, got %q (%v)", body, name, content, err)
+			}
+		}
+	})
+
+	t.Run("deletes everything with delete and force", func(t *testing.T) {
+		tmpDir, files := setup(t)
+
+		result, err := Prune(tmpDir, files, PruneOptions{Delete: true, Force: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.Deleted) != 2 {
+			t.Errorf("Expected 2 deleted, got %v", result.Deleted)
+		}
+		remaining, _ := ScanExports(tmpDir)
+		if len(remaining) != 0 {
+			t.Errorf("Expected no files left, got %+v", remaining)
+		}
+	})
+
+	t.Run("removes folder links and state for pruned files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.MirrorFolders = true
+		doc := Document{ID: "gone", Title: "Sync", CreatedAt: "2025-01-25T10:00:00Z", NotesMarkdown: "Sync notes here", Folders: []string{"Alpha", "Beta"}}
+		state := &CacheState{Documents: map[string]Document{"gone": doc}}
+		if _, err := exp.Export(state); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		files, err := ScanExports(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := Prune(tmpDir, FindOrphans(files, nil), PruneOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		link := filepath.Join("Beta", "2025-01-25_Sync.md")
+		if len(result.Unlinked) != 1 || result.Unlinked[0] != link {
+			t.Errorf("Expected %s unlinked, got %v", link, result.Unlinked)
+		}
+		if _, err := os.Lstat(filepath.Join(tmpDir, "Beta")); !os.IsNotExist(err) {
+			t.Errorf("Expected the emptied folder to be removed, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, ArchiveDirName, "Alpha", "2025-01-25_Sync.md")); err != nil {
+			t.Errorf("Expected the file in the archive: %v", err)
+		}
+
+		saved, err := LoadExportState(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := saved.Documents["gone"]; ok {
+			t.Errorf("Expected the pruned document to be dropped from the state, got %+v", saved.Documents)
+		}
+	})
+}
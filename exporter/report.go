@@ -0,0 +1,91 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Report formats supported by WriteReport.
+const (
+	ReportText = "text"
+	ReportJSON = "json"
+)
+
+// CacheInfo describes the cache file an export was run against.
+type CacheInfo struct {
+	Path      string `json:"path"`
+	Version   int    `json:"version"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// Report is a machine-readable summary of an export run.
+type Report struct {
+	Status         string           `json:"status"`
+	OutputDir      string           `json:"output_dir"`
+	Cache          CacheInfo        `json:"cache"`
+	CacheUnchanged bool             `json:"cache_unchanged"`
+	Written        int              `json:"written"`
+	Skipped        int              `json:"skipped"`
+	Empty          int              `json:"empty"`
+	ErrorCount     int              `json:"error_count"`
+	DurationMS     int64            `json:"duration_ms"`
+	Documents      []DocumentResult `json:"documents"`
+	Errors         []ExportError    `json:"errors"`
+	IndexErrors    []string         `json:"index_errors"`
+}
+
+// NewReport builds a Report from an export result.
+// The duration covers the whole run, including cache loading.
+func NewReport(result *ExportResult, outputDir string, cache CacheInfo, duration time.Duration) *Report {
+	status := "ok"
+	if len(result.Errors) > 0 || len(result.IndexErrors) > 0 {
+		status = "error"
+	}
+
+	documents := result.Documents
+	if documents == nil {
+		documents = []DocumentResult{}
+	}
+	errors := result.Errors
+	if errors == nil {
+		errors = []ExportError{}
+	}
+	indexErrors := result.IndexErrors
+	if indexErrors == nil {
+		indexErrors = []string{}
+	}
+
+	return &Report{
+		Status:         status,
+		OutputDir:      outputDir,
+		Cache:          cache,
+		CacheUnchanged: result.CacheUnchanged,
+		Written:        result.Written,
+		Skipped:        result.Skipped,
+		Empty:          result.Empty,
+		ErrorCount:     len(result.Errors),
+		DurationMS:     duration.Milliseconds(),
+		Documents:      documents,
+		Errors:         errors,
+		IndexErrors:    indexErrors,
+	}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// ValidateReportFormat returns an error if format is not a supported report format.
+func ValidateReportFormat(format string) error {
+	switch format {
+	case ReportText, ReportJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format %q (expected %q or %q)", format, ReportText, ReportJSON)
+	}
+}
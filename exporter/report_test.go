@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewReport(t *testing.T) {
+	t.Run("summarizes a successful export", func(t *testing.T) {
+		result := &ExportResult{
+			Written: 1,
+			Skipped: 1,
+			Documents: []DocumentResult{
+				{DocumentID: "doc1", Title: "One", Filename: "2026-01-21_One.md", Action: ActionWritten},
+				{DocumentID: "doc2", Title: "Two", Filename: "2026-01-21_Two.md", Action: ActionSkipped},
+			},
+		}
+		cache := CacheInfo{Path: "/tmp/cache-v6.json", Version: 6, SizeBytes: 2048}
+
+		report := NewReport(result, "/tmp/out", cache, 1500*time.Millisecond)
+
+		if report.Status != "ok" {
+			t.Errorf("Expected status 'ok', got %q", report.Status)
+		}
+		if report.DurationMS != 1500 {
+			t.Errorf("Expected 1500ms duration, got %d", report.DurationMS)
+		}
+		if report.Cache.Version != 6 {
+			t.Errorf("Expected cache version 6, got %d", report.Cache.Version)
+		}
+		if len(report.Documents) != 2 {
+			t.Errorf("Expected 2 documents, got %d", len(report.Documents))
+		}
+	})
+
+	t.Run("marks status as error when documents failed", func(t *testing.T) {
+		result := &ExportResult{
+			Errors: []ExportError{{DocumentID: "doc1", Title: "One", Error: "boom"}},
+		}
+
+		report := NewReport(result, "/tmp/out", CacheInfo{}, 0)
+
+		if report.Status != "error" {
+			t.Errorf("Expected status 'error', got %q", report.Status)
+		}
+		if report.ErrorCount != 1 {
+			t.Errorf("Expected 1 error, got %d", report.ErrorCount)
+		}
+	})
+
+	t.Run("writes empty lists rather than null", func(t *testing.T) {
+		report := NewReport(&ExportResult{}, "/tmp/out", CacheInfo{}, 0)
+
+		var buf bytes.Buffer
+		if err := report.WriteJSON(&buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Report is not valid JSON: %v", err)
+		}
+		if strings.Contains(buf.String(), "null") {
+			t.Errorf("Expected no null values in report, got %s", buf.String())
+		}
+	})
+}
+
+func TestValidateReportFormat(t *testing.T) {
+	for _, format := range []string{ReportText, ReportJSON} {
+		if err := ValidateReportFormat(format); err != nil {
+			t.Errorf("ValidateReportFormat(%q) returned error: %v", format, err)
+		}
+	}
+	if err := ValidateReportFormat("xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,214 @@
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SeriesDirName is the folder inside the output directory that series index
+// pages are written to.
+const SeriesDirName = "_series"
+
+// datePattern matches dates people put in meeting titles, such as
+// "2026-01-21", "1/21" or "01/21/26".
+var datePattern = regexp.MustCompile(`\b\d{4}-\d{1,2}-\d{1,2}\b|\b\d{1,2}/\d{1,2}(/\d{2,4})?\b`)
+
+// NormalizeTitle reduces a meeting title to the part that stays the same
+// across occurrences of a recurring meeting: lowercase, without dates or
+// punctuation, and with whitespace collapsed.
+func NormalizeTitle(title string) string {
+	title = datePattern.ReplaceAllString(strings.ToLower(title), " ")
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, title)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// SeriesKey identifies the recurring series a document belongs to: its
+// calendar series when it has one, otherwise its normalized title. Returns
+// "" for documents without a usable title.
+func SeriesKey(doc *Document) string {
+	if event := doc.GoogleCalendarEvent; event != nil && event.RecurringEventID != "" {
+		return "event:" + event.RecurringEventID
+	}
+	if title := NormalizeTitle(doc.Title); title != "" {
+		return "title:" + title
+	}
+	return ""
+}
+
+// resolveSeries moves meetings keyed by title into the calendar series of
+// another meeting with the same normalized title, so occurrences recorded
+// before the calendar was linked, or read back from exported files, stay in
+// their series.
+func resolveSeries(meetings []Meeting) {
+	events := make(map[string]string)
+	for _, m := range meetings {
+		if strings.HasPrefix(m.Series, "event:") {
+			if title := NormalizeTitle(m.Title); title != "" {
+				events["title:"+title] = m.Series
+			}
+		}
+	}
+	for i := range meetings {
+		if event, ok := events[meetings[i].Series]; ok {
+			meetings[i].Series = event
+		}
+	}
+}
+
+// GroupSeries groups meetings, which must be sorted by date, by series.
+// Only series with more than one meeting are returned, ordered by key.
+func GroupSeries(meetings []Meeting) [][]Meeting {
+	bySeries := make(map[string][]Meeting)
+	var keys []string
+	for _, m := range meetings {
+		if m.Series == "" {
+			continue
+		}
+		if _, ok := bySeries[m.Series]; !ok {
+			keys = append(keys, m.Series)
+		}
+		bySeries[m.Series] = append(bySeries[m.Series], m)
+	}
+	sort.Strings(keys)
+
+	var groups [][]Meeting
+	for _, key := range keys {
+		if len(bySeries[key]) > 1 {
+			groups = append(groups, bySeries[key])
+		}
+	}
+	return groups
+}
+
+// seriesMeetings describes the documents exported this run as meetings, in
+// date order, for grouping into series. Documents without a file are left out.
+func seriesMeetings(docs []Document, outcomes []documentOutcome) []Meeting {
+	var meetings []Meeting
+	for i := range docs {
+		outcome := outcomes[i]
+		if outcome.action != ActionWritten && outcome.action != ActionSkipped {
+			continue
+		}
+		m := Meeting{
+			ID:            docs[i].ID,
+			Title:         docs[i].Title,
+			HasTranscript: outcome.state != nil && outcome.state.Transcript,
+			Stats:         outcome.stats,
+			Series:        SeriesKey(&docs[i]),
+			File:          outcome.filename,
+		}
+		m.Date, _ = parseTimestamp(docs[i].CreatedAt)
+		meetings = append(meetings, m)
+	}
+
+	resolveSeries(meetings)
+	sort.SliceStable(meetings, func(i, j int) bool {
+		return meetings[i].Date.Before(meetings[j].Date)
+	})
+	return meetings
+}
+
+// writeSeriesIndexes writes an index page for every series with more than
+// one meeting, and removes index pages for series that no longer have one.
+// With series indexes turned off, every existing page is removed.
+func (e *Exporter) writeSeriesIndexes(meetings []Meeting) error {
+	dir := filepath.Join(e.OutputDir, SeriesDirName)
+	logger := e.logger()
+
+	wanted := make(map[string]bool)
+	if e.SeriesIndex {
+		for _, group := range GroupSeries(meetings) {
+			name := seriesFilename(group[len(group)-1].Title, wanted)
+			wanted[name] = true
+
+			written, err := writeIndexPage(filepath.Join(dir, name), e.formatSeriesIndex(group))
+			if err != nil {
+				return err
+			}
+			if written {
+				logger.Info("wrote series index", "file", filepath.Join(SeriesDirName, name), "meetings", len(group))
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if wanted[entry.Name()] || !entry.Type().IsRegular() || !isIndexPage(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove series index: %w", err)
+		}
+		logger.Info("removed series index", "file", filepath.Join(SeriesDirName, entry.Name()))
+	}
+	removeEmptyDirs(e.OutputDir, dir)
+
+	return nil
+}
+
+// seriesFilename returns the index page name for a series title, numbering
+// it when another series already took the name.
+func seriesFilename(title string, taken map[string]bool) string {
+	base := strings.TrimSpace(removeUnsafeChars(title))
+	base = strings.TrimLeft(base, ".")
+	if base == "" {
+		base = "Untitled"
+	}
+	if runes := []rune(base); len(runes) > 100 {
+		base = string(runes[:100])
+	}
+
+	name := base + ".md"
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s (%d).md", base, n)
+	}
+	return name
+}
+
+// formatSeriesIndex renders the index page for one series, linking each
+// meeting in date order.
+func (e *Exporter) formatSeriesIndex(group []Meeting) string {
+	dir := filepath.Join(e.OutputDir, SeriesDirName)
+	first, last := group[0], group[len(group)-1]
+
+	title := last.Title
+	if title == "" {
+		title = "Untitled"
+	}
+
+	lines := []string{IndexMarker, "# " + title, ""}
+	lines = append(lines, fmt.Sprintf("%d meetings from %s to %s.", len(group), formatMeetingDay(first.Date), formatMeetingDay(last.Date)))
+	lines = append(lines, "")
+
+	for _, m := range group {
+		text := "Unknown date"
+		if !m.Date.IsZero() {
+			text = m.Date.Format("2006-01-02 15:04")
+		}
+		if m.Title != title && m.Title != "" {
+			text += " " + m.Title
+		}
+		line := "- " + markdownLink(escapeTableText(text), dir, filepath.Join(e.OutputDir, m.File))
+		if m.HasTranscript {
+			line += " (transcript)"
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+
+	return strings.Join(lines, "\n")
+}
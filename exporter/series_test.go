@@ -0,0 +1,188 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := map[string]string{
+		"Weekly Platform Sync":              "weekly platform sync",
+		"Weekly Platform Sync - 2026-01-21": "weekly platform sync",
+		"weekly platform  sync (1/21)":      "weekly platform sync",
+		"1:1 Alice / Bob":                   "1 1 alice bob",
+		"   ":                               "",
+	}
+	for title, want := range tests {
+		if got := NormalizeTitle(title); got != want {
+			t.Errorf("NormalizeTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestSeriesKey(t *testing.T) {
+	t.Run("prefers the calendar series", func(t *testing.T) {
+		doc := &Document{Title: "Sync", GoogleCalendarEvent: &CalendarEvent{RecurringEventID: "abc"}}
+		if got := SeriesKey(doc); got != "event:abc" {
+			t.Errorf("Unexpected key: %q", got)
+		}
+	})
+
+	t.Run("falls back to the normalized title", func(t *testing.T) {
+		doc := &Document{Title: "Sync 2026-01-21", GoogleCalendarEvent: &CalendarEvent{}}
+		if got := SeriesKey(doc); got != "title:sync" {
+			t.Errorf("Unexpected key: %q", got)
+		}
+		if got := SeriesKey(&Document{}); got != "" {
+			t.Errorf("Expected no key without a title, got %q", got)
+		}
+	})
+
+	t.Run("moves title matches into the calendar series", func(t *testing.T) {
+		meetings := []Meeting{
+			{Title: "Sync", Series: "event:abc"},
+			{Title: "sync", Series: "title:sync"},
+			{Title: "Other", Series: "title:other"},
+		}
+		resolveSeries(meetings)
+		if meetings[1].Series != "event:abc" || meetings[2].Series != "title:other" {
+			t.Errorf("Unexpected series: %+v", meetings)
+		}
+	})
+}
+
+func TestSeriesFilename(t *testing.T) {
+	t.Run("cuts long titles between characters", func(t *testing.T) {
+		name := seriesFilename("x"+strings.Repeat("é", 120), map[string]bool{})
+		if !utf8.ValidString(name) || name != "x"+strings.Repeat("é", 99)+".md" {
+			t.Errorf("Expected 100 characters, got %q", name)
+		}
+	})
+
+	t.Run("numbers names already taken", func(t *testing.T) {
+		if got := seriesFilename("Sync", map[string]bool{"Sync.md": true}); got != "Sync (2).md" {
+			t.Errorf("Expected a numbered name, got %q", got)
+		}
+	})
+}
+
+func TestExporterSeriesIndex(t *testing.T) {
+	sync := func(id, date, recurring string) Document {
+		doc := Document{ID: id, Title: "Weekly Sync", CreatedAt: date + "T10:00:00Z", NotesMarkdown: "Some notes here"}
+		if recurring != "" {
+			doc.GoogleCalendarEvent = &CalendarEvent{RecurringEventID: recurring}
+		}
+		return doc
+	}
+	newState := func() *CacheState {
+		return &CacheState{
+			Documents: map[string]Document{
+				"b":     sync("b", "2026-01-26", "series"),
+				"a":     sync("a", "2026-01-19", ""),
+				"c":     sync("c", "2026-02-02", "series"),
+				"other": {ID: "other", Title: "One-off", CreatedAt: "2026-01-20T10:00:00Z", NotesMarkdown: "Some notes here"},
+			},
+			Transcripts: map[string][]TranscriptEntry{
+				"c": {{Source: "microphone", Text: "Hello"}},
+			},
+		}
+	}
+
+	t.Run("links each meeting of a series in date order", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.SeriesIndex = true
+
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, SeriesDirName, "Weekly Sync.md"))
+		if err != nil {
+			t.Fatalf("Expected series index: %v", err)
+		}
+		want := IndexMarker + `
+# Weekly Sync
+
+3 meetings from 2026-01-19 to 2026-02-02.
+
+- [2026-01-19 10:00](<../2026-01-19_Weekly Sync.md>)
+- [2026-01-26 10:00](<../2026-01-26_Weekly Sync.md>)
+- [2026-02-02 10:00](<../2026-02-02_Weekly Sync.md>) (transcript)
+`
+		if string(content) != want {
+			t.Errorf("Unexpected series index:\n%s", content)
+		}
+
+		entries, _ := os.ReadDir(filepath.Join(tmpDir, SeriesDirName))
+		if len(entries) != 1 {
+			t.Errorf("Expected no index for one-off meetings, got %d pages", len(entries))
+		}
+
+		// Index pages aren't meeting files
+		files, err := ScanExports(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 4 {
+			t.Errorf("Expected only meeting files to be scanned, got %d", len(files))
+		}
+	})
+
+	t.Run("escapes link text", func(t *testing.T) {
+		exp := NewExporter(t.TempDir())
+		date := time.Date(2026, 1, 19, 10, 0, 0, 0, time.UTC)
+		got := exp.formatSeriesIndex([]Meeting{
+			{Title: `Sync [draft] | A\B`, Date: date, File: "a.md"},
+			{Title: "Sync", Date: date.AddDate(0, 0, 7), File: "b.md"},
+		})
+		if want := `- [2026-01-19 10:00 Sync \[draft\] \| A\\B](<../a.md>)`; !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	})
+
+	t.Run("removes pages for series that are gone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		exp := NewExporter(tmpDir)
+		exp.SeriesIndex = true
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+		mine := filepath.Join(tmpDir, SeriesDirName, "My notes.md")
+		writeTestFile(t, mine, "# Written by hand\n")
+
+		exp.SeriesIndex = false
+		if _, err := exp.Export(newState()); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := os.Stat(filepath.Join(tmpDir, SeriesDirName, "Weekly Sync.md")); !os.IsNotExist(err) {
+			t.Errorf("Expected series index to be removed, got %v", err)
+		}
+		if _, err := os.Stat(mine); err != nil {
+			t.Errorf("Expected hand-written page to be kept: %v", err)
+		}
+	})
+
+	t.Run("never replaces pages it didn't write", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, SeriesDirName, "Weekly Sync.md"), "# Written by hand\n")
+
+		exp := NewExporter(tmpDir)
+		exp.SeriesIndex = true
+		result, err := exp.Export(newState())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.IndexErrors) != 1 || result.Written == 0 {
+			t.Errorf("Expected an index error with the documents still exported, got %+v", result)
+		}
+		if report := NewReport(result, tmpDir, CacheInfo{}, 0); report.Status != "error" || len(report.IndexErrors) != 1 {
+			t.Errorf("Expected the report to include the index error, got %+v", report)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StateFilename is the file in the output directory where granary records
+// what it exported, so later runs can skip unchanged work.
+const StateFilename = ".granary-state.json"
+
+// ExportState is persisted in the output directory between runs.
+type ExportState struct {
+	// Version is the granary version that wrote the state. Output formatting
+	// can change between versions, so a different version forces a full run.
+	Version string `json:"version,omitempty"`
+	// Format is the formatting options of the last run. Changing them
+	// invalidates the cache check even when the cache itself is unchanged.
+	Format FormatOptions `json:"format"`
+	// Folders records whether the last run mirrored Granola folders.
+	Folders bool `json:"folders,omitempty"`
+	// SeriesIndex and Index record which index pages the last run wrote.
+	SeriesIndex bool `json:"series_index,omitempty"`
+	Index       bool `json:"index,omitempty"`
+	// ByMonth records whether the last run wrote files into month
+	// directories.
+	ByMonth   bool                     `json:"by_month,omitempty"`
+	Cache     *CacheFingerprint        `json:"cache,omitempty"`
+	Documents map[string]DocumentState `json:"documents,omitempty"`
+}
+
+// DocumentState records how a document was last exported. The input hash
+// covers everything that feeds the rendered file; size and mtime detect
+// files edited outside granary.
+type DocumentState struct {
+	Filename  string    `json:"filename"`
+	InputHash string    `json:"input_hash"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	// Links are symlinks to the file from the document's other folders.
+	Links []string `json:"links,omitempty"`
+	// Words and Transcript describe the file for the index page.
+	Words      int  `json:"words,omitempty"`
+	Transcript bool `json:"transcript,omitempty"`
+	// Stats describe the transcript in the file, which may have been kept
+	// from an earlier export, so skipped documents can report them.
+	Stats *TranscriptStats `json:"stats,omitempty"`
+}
+
+// CacheFingerprint identifies the contents of a cache file.
+type CacheFingerprint struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	SHA256  string    `json:"sha256"`
+}
+
+// LoadExportState reads the state file from dir.
+// A missing state file yields an empty state.
+func LoadExportState(dir string) (*ExportState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, StateFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return &ExportState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export state: %w", err)
+	}
+
+	var state ExportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse export state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save writes the state file to dir atomically.
+func (s *ExportState) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export state: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, StateFilename+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, StateFilename)); err != nil {
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+	return nil
+}
+
+// newDocumentState records the current state of a written file.
+// Returns nil if the file can't be stat'ed, so the next run compares on disk.
+func newDocumentState(filename, inputHash, path, content string, hasTranscript bool, stats *TranscriptStats) *DocumentState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	return &DocumentState{
+		Filename:   filename,
+		InputHash:  inputHash,
+		Size:       info.Size(),
+		ModTime:    info.ModTime().UTC(),
+		Words:      len(strings.Fields(content)),
+		Transcript: hasTranscript,
+		Stats:      stats,
+	}
+}
+
+// matches reports whether a document with the given inputs can be skipped
+// because it was exported from identical inputs to a file that is unchanged
+// since. Only the file's metadata is checked, never its contents.
+func (s *DocumentState) matches(filename, inputHash, path string) bool {
+	if s == nil || s.Filename != filename || s.InputHash != inputHash {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Size() == s.Size && info.ModTime().UTC().Equal(s.ModTime)
+}
+
+// documentInputHash hashes everything that determines a document's rendered file.
+func documentInputHash(doc *Document, transcript []TranscriptEntry, opts FormatOptions) string {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	enc.Encode(doc)
+	enc.Encode(doc.Folders)
+	enc.Encode(doc.Panels)
+	enc.Encode(transcript)
+	enc.Encode(opts)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CheckCacheFingerprint fingerprints the cache at path and reports whether it
+// is unchanged from previous. Matching path, size and mtime are trusted
+// without reading the file; otherwise the content hash decides.
+func CheckCacheFingerprint(path string, previous *CacheFingerprint) (CacheFingerprint, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return CacheFingerprint{}, false, fmt.Errorf("failed to stat cache file: %w", err)
+	}
+
+	current := CacheFingerprint{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UTC(),
+	}
+
+	if previous != nil && previous.Path == current.Path && previous.Size == current.Size &&
+		previous.ModTime.Equal(current.ModTime) && previous.SHA256 != "" {
+		current.SHA256 = previous.SHA256
+		return current, true, nil
+	}
+
+	current.SHA256, err = hashFile(path)
+	if err != nil {
+		return CacheFingerprint{}, false, err
+	}
+
+	unchanged := previous != nil && previous.Path == current.Path && previous.SHA256 == current.SHA256
+	return current, unchanged, nil
+}
+
+// hashFile returns the hex SHA-256 of a file's contents.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cache file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash cache file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
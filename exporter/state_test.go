@@ -0,0 +1,110 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportState(t *testing.T) {
+	t.Run("missing state file yields empty state", func(t *testing.T) {
+		state, err := LoadExportState(t.TempDir())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state.Cache != nil || state.Version != "" {
+			t.Errorf("Expected empty state, got %+v", state)
+		}
+	})
+
+	t.Run("round trips through save and load", func(t *testing.T) {
+		dir := t.TempDir()
+		saved := &ExportState{
+			Version: "1.2.3",
+			Cache: &CacheFingerprint{
+				Path:    "/tmp/cache-v6.json",
+				Size:    42,
+				ModTime: time.Date(2026, 1, 21, 10, 0, 0, 0, time.UTC),
+				SHA256:  "abc",
+			},
+		}
+		if err := saved.Save(dir); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		loaded, err := LoadExportState(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if loaded.Version != "1.2.3" || *loaded.Cache != *saved.Cache {
+			t.Errorf("Expected %+v, got %+v", saved.Cache, loaded.Cache)
+		}
+
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("Expected only the state file, got %d entries", len(entries))
+		}
+	})
+}
+
+func TestCheckCacheFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache-v6.json")
+	if err := os.WriteFile(path, []byte(`{"cache":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, unchanged, err := CheckCacheFingerprint(path, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if unchanged {
+		t.Error("Expected a cache without previous fingerprint to count as changed")
+	}
+	if first.SHA256 == "" || first.Size != 12 {
+		t.Errorf("Unexpected fingerprint: %+v", first)
+	}
+
+	t.Run("unchanged when metadata matches", func(t *testing.T) {
+		_, unchanged, err := CheckCacheFingerprint(path, &first)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !unchanged {
+			t.Error("Expected cache to be unchanged")
+		}
+	})
+
+	t.Run("unchanged when touched but identical", func(t *testing.T) {
+		later := first.ModTime.Add(time.Hour)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+
+		current, unchanged, err := CheckCacheFingerprint(path, &first)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !unchanged {
+			t.Error("Expected identical content to count as unchanged")
+		}
+		if !current.ModTime.Equal(later) {
+			t.Errorf("Expected new mtime %v, got %v", later, current.ModTime)
+		}
+	})
+
+	t.Run("changed when content differs", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte(`{"cache":{"a":1}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, unchanged, err := CheckCacheFingerprint(path, &first)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if unchanged {
+			t.Error("Expected modified cache to count as changed")
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TranscriptStats summarizes who spoke and for how long in a transcript.
+// Times are only counted for entries with parseable timestamps, so
+// transcripts read back from exported files have word and turn counts but
+// no durations.
+type TranscriptStats struct {
+	DurationSeconds  int64          `json:"duration_seconds"`
+	Turns            int            `json:"turns"`
+	Words            int            `json:"words"`
+	Speakers         []SpeakerStats `json:"speakers"`
+	LongestMonologue *Monologue     `json:"longest_monologue,omitempty"`
+}
+
+// SpeakerStats are the totals for one transcript source.
+type SpeakerStats struct {
+	Source      string `json:"source"`
+	Speaker     string `json:"speaker"`
+	TalkSeconds int64  `json:"talk_seconds"`
+	Words       int    `json:"words"`
+	Turns       int    `json:"turns"`
+}
+
+// Monologue is the longest uninterrupted turn by one speaker.
+type Monologue struct {
+	Speaker         string `json:"speaker"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	Words           int    `json:"words"`
+}
+
+// ComputeTranscriptStats computes statistics for a transcript. A turn is a
+// run of consecutive entries from the same source. Returns nil for an empty
+// transcript.
+func ComputeTranscriptStats(entries []TranscriptEntry) *TranscriptStats {
+	stats := &TranscriptStats{Speakers: []SpeakerStats{}}
+	bySource := make(map[string]*SpeakerStats)
+
+	var first, last time.Time
+	var turn *Monologue
+	var turnStart, turnEnd time.Time
+	var turnSource string
+	endTurn := func() {
+		if turn == nil {
+			return
+		}
+		if !turnStart.IsZero() && turnEnd.After(turnStart) {
+			turn.DurationSeconds = int64(turnEnd.Sub(turnStart).Seconds())
+		}
+		longest := stats.LongestMonologue
+		if longest == nil || turn.DurationSeconds > longest.DurationSeconds ||
+			(turn.DurationSeconds == longest.DurationSeconds && turn.Words > longest.Words) {
+			stats.LongestMonologue = turn
+		}
+		turn = nil
+	}
+
+	for _, entry := range entries {
+		words := len(strings.Fields(entry.Text))
+		if words == 0 {
+			continue
+		}
+
+		speaker, ok := bySource[entry.Source]
+		if !ok {
+			speaker = &SpeakerStats{Source: entry.Source, Speaker: SourceToSpeaker(entry.Source)}
+			bySource[entry.Source] = speaker
+		}
+		speaker.Words += words
+		stats.Words += words
+
+		start, hasStart := parseTimestamp(entry.StartTimestamp)
+		end, hasEnd := parseTimestamp(entry.EndTimestamp)
+		if hasStart && hasEnd && end.After(start) {
+			speaker.TalkSeconds += int64(end.Sub(start).Seconds())
+		}
+		if hasStart && (first.IsZero() || start.Before(first)) {
+			first = start
+		}
+		if hasEnd && end.After(last) {
+			last = end
+		}
+
+		if turn == nil || entry.Source != turnSource {
+			endTurn()
+			turn = &Monologue{Speaker: speaker.Speaker}
+			turnSource = entry.Source
+			turnStart, turnEnd = time.Time{}, time.Time{}
+			if hasStart {
+				turnStart = start
+			}
+			speaker.Turns++
+			stats.Turns++
+		}
+		turn.Words += words
+		if hasEnd {
+			turnEnd = end
+		}
+	}
+	endTurn()
+
+	if stats.Words == 0 {
+		return nil
+	}
+	if !first.IsZero() && last.After(first) {
+		stats.DurationSeconds = int64(last.Sub(first).Seconds())
+	}
+
+	for _, speaker := range bySource {
+		stats.Speakers = append(stats.Speakers, *speaker)
+	}
+	sort.Slice(stats.Speakers, func(i, j int) bool {
+		return stats.Speakers[i].Speaker < stats.Speakers[j].Speaker
+	})
+
+	return stats
+}
+
+// formatStatsSection renders stats as the lines of a "Stats" section.
+func formatStatsSection(stats *TranscriptStats) []string {
+	lines := []string{"## Stats", ""}
+
+	if stats.DurationSeconds > 0 {
+		lines = append(lines, fmt.Sprintf("Duration: %s", formatSeconds(stats.DurationSeconds)))
+	}
+	lines = append(lines, fmt.Sprintf("Turns: %s", NumberWithCommas(stats.Turns)))
+	if m := stats.LongestMonologue; m != nil {
+		if m.DurationSeconds > 0 {
+			lines = append(lines, fmt.Sprintf("Longest monologue: %s, %s (%s words)", m.Speaker, formatSeconds(m.DurationSeconds), NumberWithCommas(m.Words)))
+		} else {
+			lines = append(lines, fmt.Sprintf("Longest monologue: %s, %s words", m.Speaker, NumberWithCommas(m.Words)))
+		}
+	}
+	lines = append(lines, "")
+
+	// Share of talk time when timestamps are known, otherwise of words
+	var talkTotal int64
+	for _, s := range stats.Speakers {
+		talkTotal += s.TalkSeconds
+	}
+
+	lines = append(lines, "| Speaker | Talk time | Share | Words | Turns |")
+	lines = append(lines, "| --- | --- | --- | --- | --- |")
+	for _, s := range stats.Speakers {
+		talk := "-"
+		share := float64(s.Words) / float64(stats.Words)
+		if talkTotal > 0 {
+			talk = formatSeconds(s.TalkSeconds)
+			share = float64(s.TalkSeconds) / float64(talkTotal)
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %s | %.0f%% | %s | %s |",
+			s.Speaker, talk, share*100, NumberWithCommas(s.Words), NumberWithCommas(s.Turns)))
+	}
+	lines = append(lines, "")
+
+	return lines
+}
+
+// formatSeconds formats a number of seconds as "1h 5m", "12m 30s" or "45s".
+func formatSeconds(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	h := int64(d / time.Hour)
+	m := int64(d % time.Hour / time.Minute)
+	s := int64(d % time.Minute / time.Second)
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm %ds", m, s)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
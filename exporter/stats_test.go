@@ -0,0 +1,88 @@
+package exporter
+
+import "testing"
+
+func TestComputeTranscriptStats(t *testing.T) {
+	entry := func(source, start, end, text string) TranscriptEntry {
+		return TranscriptEntry{
+			Source:         source,
+			StartTimestamp: "2026-01-21T10:" + start + "Z",
+			EndTimestamp:   "2026-01-21T10:" + end + "Z",
+			Text:           text,
+		}
+	}
+
+	t.Run("totals talk time, words and turns per speaker", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("system", "00:00", "00:20", "So the plan is"),
+			entry("system", "00:20", "01:00", "to ship on Friday"),
+			entry("microphone", "01:05", "01:10", "Sounds good"),
+			entry("system", "01:10", "01:30", "Great"),
+		}
+
+		stats := ComputeTranscriptStats(entries)
+		if stats == nil {
+			t.Fatal("Expected stats")
+		}
+		if stats.DurationSeconds != 90 || stats.Turns != 3 || stats.Words != 11 {
+			t.Errorf("Unexpected totals: %+v", stats)
+		}
+		if len(stats.Speakers) != 2 {
+			t.Fatalf("Expected 2 speakers, got %+v", stats.Speakers)
+		}
+		me, them := stats.Speakers[0], stats.Speakers[1]
+		if me.Speaker != "Me" || me.TalkSeconds != 5 || me.Words != 2 || me.Turns != 1 {
+			t.Errorf("Unexpected stats for Me: %+v", me)
+		}
+		if them.Speaker != "Them" || them.TalkSeconds != 80 || them.Words != 9 || them.Turns != 2 {
+			t.Errorf("Unexpected stats for Them: %+v", them)
+		}
+
+		m := stats.LongestMonologue
+		if m == nil || m.Speaker != "Them" || m.DurationSeconds != 60 || m.Words != 8 {
+			t.Errorf("Unexpected longest monologue: %+v", m)
+		}
+	})
+
+	t.Run("counts words and turns without timestamps", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			{Source: "system", Text: "One two"},
+			{Source: "microphone", Text: "Three"},
+			{Source: "microphone", Text: "four five six"},
+		}
+
+		stats := ComputeTranscriptStats(entries)
+		if stats == nil {
+			t.Fatal("Expected stats")
+		}
+		if stats.DurationSeconds != 0 || stats.Turns != 2 || stats.Words != 6 {
+			t.Errorf("Unexpected totals: %+v", stats)
+		}
+		if m := stats.LongestMonologue; m == nil || m.Speaker != "Me" || m.Words != 4 {
+			t.Errorf("Expected the longest monologue by words, got %+v", m)
+		}
+	})
+
+	t.Run("returns nil without words", func(t *testing.T) {
+		if stats := ComputeTranscriptStats(nil); stats != nil {
+			t.Errorf("Expected nil for no entries, got %+v", stats)
+		}
+		if stats := ComputeTranscriptStats([]TranscriptEntry{{Source: "system", Text: "  "}}); stats != nil {
+			t.Errorf("Expected nil for empty entries, got %+v", stats)
+		}
+	})
+}
+
+func TestFormatSeconds(t *testing.T) {
+	tests := map[int64]string{
+		0:    "0s",
+		45:   "45s",
+		750:  "12m 30s",
+		3900: "1h 5m",
+	}
+	for seconds, want := range tests {
+		if got := formatSeconds(seconds); got != want {
+			t.Errorf("formatSeconds(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package exporter
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMergeGap is the longest pause between two entries from the same
+// source that ConsolidateTranscript still treats as one turn.
+const DefaultMergeGap = 10 * time.Second
+
+// ConsolidateTranscript merges consecutive entries from the same source into
+// a single entry per speaker turn. Two entries are merged when the pause
+// between the end of one and the start of the next is at most maxGap, or when
+// either timestamp is missing, as in transcripts read back from exported files.
+// A maxGap of zero or less never splits a turn on pauses. Entries with no text
+// are dropped.
+func ConsolidateTranscript(entries []TranscriptEntry, maxGap time.Duration) []TranscriptEntry {
+	var merged []TranscriptEntry
+	for _, entry := range entries {
+		text := strings.TrimSpace(entry.Text)
+		if text == "" {
+			continue
+		}
+		entry.Text = text
+
+		if n := len(merged); n > 0 && merged[n-1].Source == entry.Source && withinGap(merged[n-1], entry, maxGap) {
+			last := &merged[n-1]
+			last.Text += " " + text
+			if entry.EndTimestamp != "" {
+				last.EndTimestamp = entry.EndTimestamp
+			}
+			continue
+		}
+		merged = append(merged, entry)
+	}
+	return merged
+}
+
+// withinGap reports whether next starts no more than maxGap after prev ends.
+func withinGap(prev, next TranscriptEntry, maxGap time.Duration) bool {
+	if maxGap <= 0 {
+		return true
+	}
+	end, ok := parseTimestamp(prev.EndTimestamp)
+	if !ok {
+		return true
+	}
+	start, ok := parseTimestamp(next.StartTimestamp)
+	if !ok {
+		return true
+	}
+	return start.Sub(end) <= maxGap
+}
+
+// TranscriptCleanup counts the entries CleanTranscript removed.
+type TranscriptCleanup struct {
+	// Interim is the number of non-final entries replaced by a final one.
+	Interim int
+	// Duplicates is the number of repeated entries.
+	Duplicates int
+}
+
+// Removed returns the total number of entries removed.
+func (c TranscriptCleanup) Removed() int {
+	return c.Interim + c.Duplicates
+}
+
+// CleanTranscript removes entries that would repeat text in an export and
+// sorts the rest by start time. It drops:
+//   - entries whose ID was already seen, keeping a final entry over an interim one
+//   - non-final entries overlapping a final entry from the same source
+//   - entries from the same source that overlap one starting no later and
+//     repeat its text or its exact span
+//
+// Entries are only sorted when every one has a start time, so transcripts
+// read back from exported files keep their order. Timed entries are compared
+// in start order against the entries they overlap, so long transcripts take
+// about linear time.
+func CleanTranscript(entries []TranscriptEntry) ([]TranscriptEntry, TranscriptCleanup) {
+	var cleanup TranscriptCleanup
+	if len(entries) == 0 {
+		return entries, cleanup
+	}
+
+	// Same ID: keep one entry, preferring the final recognition
+	byID := make(map[string]int)
+	var unique []TranscriptEntry
+	for _, entry := range entries {
+		if entry.ID != "" {
+			if i, ok := byID[entry.ID]; ok {
+				if entry.IsFinal && !unique[i].IsFinal {
+					unique[i] = entry
+				}
+				cleanup.Duplicates++
+				continue
+			}
+			byID[entry.ID] = len(unique)
+		}
+		unique = append(unique, entry)
+	}
+
+	// Untimed entries never overlap anything, so only timed ones are compared
+	spans := make([]transcriptSpan, len(unique))
+	keep := make([]bool, len(unique))
+	var order []int
+	for i, entry := range unique {
+		spans[i] = newTranscriptSpan(entry)
+		keep[i] = true
+		if spans[i].hasStart {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return spans[order[a]].start.Before(spans[order[b]].start)
+	})
+
+	// Interim recognitions superseded by a final entry for the same speech
+	window := newOverlapWindow(spans)
+	for _, i := range order {
+		entry := unique[i]
+		for _, j := range window.overlapping(entry.Source, i) {
+			switch {
+			case entry.IsFinal && !unique[j].IsFinal && keep[j]:
+				keep[j] = false
+				cleanup.Interim++
+			case !entry.IsFinal && unique[j].IsFinal && keep[i]:
+				keep[i] = false
+				cleanup.Interim++
+			}
+		}
+		window.add(entry.Source, i)
+	}
+
+	// Repeats of an entry already kept. Overlapping entries with the same
+	// span start together, so a map finds them without the window
+	seenSpans := make(map[[3]string]bool)
+	window = newOverlapWindow(spans)
+	for _, i := range order {
+		if !keep[i] {
+			continue
+		}
+		entry := unique[i]
+		span := [3]string{entry.Source, entry.StartTimestamp, entry.EndTimestamp}
+		duplicate := seenSpans[span]
+		for _, j := range window.overlapping(entry.Source, i) {
+			if strings.TrimSpace(unique[j].Text) == strings.TrimSpace(entry.Text) {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			keep[i] = false
+			cleanup.Duplicates++
+			continue
+		}
+		seenSpans[span] = true
+		window.add(entry.Source, i)
+	}
+
+	var cleaned []TranscriptEntry
+	if len(order) == len(unique) {
+		for _, i := range order {
+			if keep[i] {
+				cleaned = append(cleaned, unique[i])
+			}
+		}
+	} else {
+		for i, entry := range unique {
+			if keep[i] {
+				cleaned = append(cleaned, entry)
+			}
+		}
+	}
+
+	return cleaned, cleanup
+}
+
+// overlapWindow holds, per source, the entries visited so far in start order
+// that may still overlap the next one.
+type overlapWindow struct {
+	spans  []transcriptSpan
+	active map[string][]int
+}
+
+func newOverlapWindow(spans []transcriptSpan) *overlapWindow {
+	return &overlapWindow{spans: spans, active: make(map[string][]int)}
+}
+
+// overlapping returns the entries from source that overlap entry i, which
+// starts no earlier than any of them. Entries ending before it are dropped,
+// since they can't overlap anything later either. The result is only valid
+// until the next call.
+func (w *overlapWindow) overlapping(source string, i int) []int {
+	active := w.active[source][:0]
+	for _, j := range w.active[source] {
+		if w.spans[j].overlaps(w.spans[i]) {
+			active = append(active, j)
+		}
+	}
+	w.active[source] = active
+	return active
+}
+
+// add makes entry i from source a candidate for later entries to overlap.
+func (w *overlapWindow) add(source string, i int) {
+	w.active[source] = append(w.active[source], i)
+}
+
+// transcriptSpan is the parsed time range of a transcript entry.
+type transcriptSpan struct {
+	start, end       time.Time
+	hasStart, hasEnd bool
+}
+
+func newTranscriptSpan(entry TranscriptEntry) transcriptSpan {
+	var s transcriptSpan
+	s.start, s.hasStart = parseTimestamp(entry.StartTimestamp)
+	s.end, s.hasEnd = parseTimestamp(entry.EndTimestamp)
+	if s.hasStart && !s.hasEnd {
+		s.end, s.hasEnd = s.start, true
+	}
+	return s
+}
+
+// overlaps reports whether two spans share time, or start together. Entries
+// that merely touch end to start don't overlap. Spans without timestamps
+// never overlap, so untimed entries are always kept.
+func (s transcriptSpan) overlaps(other transcriptSpan) bool {
+	if !s.hasStart || !other.hasStart {
+		return false
+	}
+	if s.start.Equal(other.start) {
+		return true
+	}
+	return s.start.Before(other.end) && other.start.Before(s.end)
+}
@@ -0,0 +1,231 @@
+package exporter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestConsolidateTranscript(t *testing.T) {
+	entry := func(source, start, end, text string) TranscriptEntry {
+		return TranscriptEntry{
+			Source:         source,
+			StartTimestamp: "2026-01-21T10:00:" + start + "Z",
+			EndTimestamp:   "2026-01-21T10:00:" + end + "Z",
+			Text:           text,
+		}
+	}
+
+	t.Run("merges consecutive entries from the same source", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("system", "00", "02", "So the plan"),
+			entry("system", "03", "05", " is to ship "),
+			entry("system", "05", "07", "on Friday."),
+			entry("microphone", "08", "09", "Sounds good."),
+			entry("system", "10", "11", "Great."),
+		}
+
+		got := ConsolidateTranscript(entries, DefaultMergeGap)
+
+		if len(got) != 3 {
+			t.Fatalf("Expected 3 turns, got %d: %+v", len(got), got)
+		}
+		if got[0].Text != "So the plan is to ship on Friday." {
+			t.Errorf("Unexpected merged text: %q", got[0].Text)
+		}
+		if got[0].StartTimestamp != entries[0].StartTimestamp || got[0].EndTimestamp != entries[2].EndTimestamp {
+			t.Errorf("Expected merged turn to span all entries, got %s - %s", got[0].StartTimestamp, got[0].EndTimestamp)
+		}
+		if got[1].Source != "microphone" || got[2].Text != "Great." {
+			t.Errorf("Unexpected turns: %+v", got)
+		}
+	})
+
+	t.Run("splits turns on long pauses", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("system", "00", "02", "First thought."),
+			entry("system", "30", "32", "Second thought."),
+		}
+
+		if got := ConsolidateTranscript(entries, 10*time.Second); len(got) != 2 {
+			t.Errorf("Expected pause to split the turn, got %+v", got)
+		}
+		if got := ConsolidateTranscript(entries, 0); len(got) != 1 {
+			t.Errorf("Expected no gap limit to merge, got %+v", got)
+		}
+	})
+
+	t.Run("merges entries without timestamps", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			{Source: "system", Text: "One"},
+			{Source: "system", Text: "two"},
+			{Source: "microphone", Text: "Three"},
+		}
+
+		got := ConsolidateTranscript(entries, DefaultMergeGap)
+		if len(got) != 2 || got[0].Text != "One two" {
+			t.Errorf("Unexpected turns: %+v", got)
+		}
+	})
+
+	t.Run("drops empty entries", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			{Source: "system", Text: "One"},
+			{Source: "microphone", Text: "   "},
+			{Source: "system", Text: "two"},
+		}
+
+		got := ConsolidateTranscript(entries, DefaultMergeGap)
+		if len(got) != 1 || got[0].Text != "One two" {
+			t.Errorf("Unexpected turns: %+v", got)
+		}
+	})
+}
+
+func TestCleanTranscript(t *testing.T) {
+	entry := func(id, source, start, end, text string, final bool) TranscriptEntry {
+		return TranscriptEntry{
+			ID:             id,
+			Source:         source,
+			StartTimestamp: "2026-01-21T10:00:" + start + "Z",
+			EndTimestamp:   "2026-01-21T10:00:" + end + "Z",
+			Text:           text,
+			IsFinal:        final,
+		}
+	}
+	texts := func(entries []TranscriptEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Text)
+		}
+		return out
+	}
+
+	t.Run("drops interim entries covered by a final one", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("a", "system", "00", "02", "So the", false),
+			entry("b", "system", "00", "04", "So the plan is", true),
+			entry("c", "microphone", "01", "02", "Mm", false),
+			entry("d", "system", "04", "06", "to ship", false),
+		}
+
+		got, cleanup := CleanTranscript(entries)
+
+		want := []string{"So the plan is", "Mm", "to ship"}
+		if fmt.Sprint(texts(got)) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, texts(got))
+		}
+		if cleanup.Interim != 1 || cleanup.Duplicates != 0 {
+			t.Errorf("Unexpected cleanup counts: %+v", cleanup)
+		}
+	})
+
+	t.Run("de-duplicates by ID, preferring final entries", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("a", "system", "00", "02", "Hel", false),
+			entry("a", "system", "00", "02", "Hello", true),
+			entry("a", "system", "00", "02", "Hello", true),
+		}
+
+		got, cleanup := CleanTranscript(entries)
+
+		if len(got) != 1 || got[0].Text != "Hello" || !got[0].IsFinal {
+			t.Errorf("Expected the final entry, got %+v", got)
+		}
+		if cleanup.Duplicates != 2 || cleanup.Removed() != 2 {
+			t.Errorf("Unexpected cleanup counts: %+v", cleanup)
+		}
+	})
+
+	t.Run("de-duplicates overlapping repeats", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("a", "system", "00", "03", "Welcome everyone", true),
+			entry("b", "system", "01", "03", "Welcome everyone", true),
+			entry("c", "system", "00", "03", "Welcome, everyone", true),
+			entry("d", "microphone", "00", "03", "Welcome everyone", true),
+			entry("e", "system", "10", "12", "Welcome everyone", true),
+		}
+
+		got, cleanup := CleanTranscript(entries)
+
+		if len(got) != 3 {
+			t.Errorf("Expected 3 entries, got %+v", texts(got))
+		}
+		if cleanup.Duplicates != 2 {
+			t.Errorf("Unexpected cleanup counts: %+v", cleanup)
+		}
+	})
+
+	t.Run("sorts by start time", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("b", "system", "05", "06", "second", true),
+			entry("a", "microphone", "01", "02", "first", true),
+			entry("c", "system", "09", "10", "third", true),
+		}
+
+		got, _ := CleanTranscript(entries)
+
+		want := []string{"first", "second", "third"}
+		if fmt.Sprint(texts(got)) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, texts(got))
+		}
+	})
+
+	t.Run("keeps untimed entries in order", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			{Source: "system", Text: "b"},
+			{Source: "system", Text: "b"},
+			{Source: "microphone", Text: "a"},
+		}
+
+		got, cleanup := CleanTranscript(entries)
+
+		if fmt.Sprint(texts(got)) != "[b b a]" || cleanup.Removed() != 0 {
+			t.Errorf("Expected untimed entries untouched, got %v (%+v)", texts(got), cleanup)
+		}
+	})
+
+	t.Run("compares entries with every entry they overlap", func(t *testing.T) {
+		entries := []TranscriptEntry{
+			entry("late", "system", "40", "41", "and more", false),
+			entry("long", "system", "00", "50", "A long final entry", true),
+			entry("short", "system", "10", "11", "Something else", true),
+			entry("early", "microphone", "20", "22", "Right", false),
+			entry("final", "microphone", "21", "23", "Right, yes", true),
+			entry("repeat", "system", "45", "46", "Something else", true),
+		}
+
+		got, cleanup := CleanTranscript(entries)
+
+		want := []string{"A long final entry", "Something else", "Right, yes", "Something else"}
+		if fmt.Sprint(texts(got)) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, texts(got))
+		}
+		if cleanup.Interim != 2 || cleanup.Duplicates != 0 {
+			t.Errorf("Unexpected cleanup counts: %+v", cleanup)
+		}
+	})
+}
+
+func BenchmarkCleanTranscript(b *testing.B) {
+	start := time.Date(2026, 1, 21, 10, 0, 0, 0, time.UTC)
+	var entries []TranscriptEntry
+	for i := range 5000 {
+		at := start.Add(time.Duration(i) * 2 * time.Second)
+		for _, final := range []bool{false, true} {
+			entries = append(entries, TranscriptEntry{
+				ID:             fmt.Sprintf("%d-%v", i, final),
+				Source:         []string{"microphone", "system"}[i%2],
+				StartTimestamp: at.Format(time.RFC3339),
+				EndTimestamp:   at.Add(3 * time.Second).Format(time.RFC3339),
+				Text:           fmt.Sprintf("fragment %d", i),
+				IsFinal:        final,
+			})
+		}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		CleanTranscript(entries)
+	}
+}
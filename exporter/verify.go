@@ -0,0 +1,177 @@
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// transcriptLineRegex matches a single well-formed transcript line.
+var transcriptLineRegex = regexp.MustCompile(`^\*\*\w+:\*\* \S`)
+
+// conflictCopyRegex matches filenames produced by sync tools for conflicting edits
+// (Syncthing, Dropbox, OneDrive, Google Drive).
+var conflictCopyRegex = regexp.MustCompile(`(?i)(\.sync-conflict-|\(conflicted copy|\(conflict\b|-conflict-)`)
+
+// numberedCopyRegex matches copies like "Name 2.md" created by iCloud and Finder.
+var numberedCopyRegex = regexp.MustCompile(`^(.+) \d+\.md$`)
+
+// Problem describes an integrity issue with an exported file.
+type Problem struct {
+	File      string `json:"file"`
+	MeetingID string `json:"meeting_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// VerifyResult holds the outcome of verifying an output directory.
+type VerifyResult struct {
+	Files    int
+	Problems []Problem
+}
+
+// Verify re-parses every exported file in outputDir and checks its header,
+// that its meeting ID is a known document, that its transcript parses
+// cleanly, and that it is neither a duplicate nor a sync-conflict copy.
+func Verify(outputDir string, docs map[string]Document) (*VerifyResult, error) {
+	files, err := ScanExports(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{Files: len(files)}
+	add := func(f ExportedFile, format string, args ...any) {
+		result.Problems = append(result.Problems, Problem{
+			File:      f.RelPath,
+			MeetingID: f.Header.MeetingID,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	existing := make(map[string]bool, len(files))
+	for _, f := range files {
+		existing[f.RelPath] = true
+	}
+
+	byID := make(map[string][]string)
+	for _, f := range files {
+		if f.Header.Title == "" {
+			add(f, "missing title header")
+		}
+		if f.Header.Date == "" {
+			add(f, "missing Date header")
+		} else if !validHeaderDate(f.Header.Date) {
+			add(f, "invalid Date header %q", f.Header.Date)
+		}
+
+		if f.Header.MeetingID == "" {
+			add(f, "missing Meeting ID header")
+		} else {
+			byID[f.Header.MeetingID] = append(byID[f.Header.MeetingID], f.RelPath)
+			if _, ok := docs[f.Header.MeetingID]; !ok {
+				add(f, "meeting ID not found in Granola cache")
+			}
+		}
+
+		if f.HasTranscript {
+			content, err := os.ReadFile(f.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f.RelPath, err)
+			}
+			if msg := checkTranscript(string(content)); msg != "" {
+				add(f, "%s", msg)
+			}
+		}
+
+		if isConflictCopy(f.RelPath, existing) {
+			add(f, "looks like a sync-conflict copy")
+		}
+	}
+
+	for _, f := range files {
+		paths := byID[f.Header.MeetingID]
+		if len(paths) < 2 {
+			continue
+		}
+		var others []string
+		for _, p := range paths {
+			if p != f.RelPath {
+				others = append(others, p)
+			}
+		}
+		add(f, "duplicate meeting ID, also in %s", strings.Join(others, ", "))
+	}
+
+	sort.SliceStable(result.Problems, func(i, j int) bool {
+		return result.Problems[i].File < result.Problems[j].File
+	})
+
+	return result, nil
+}
+
+// validHeaderDate reports whether a Date header was written by FormatDate.
+func validHeaderDate(date string) bool {
+	if date == "Unknown date" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02 15:04", date)
+	return err == nil
+}
+
+// checkTranscript returns a description of the first problem in the
+// transcript section, or "" if it parses cleanly.
+func checkTranscript(content string) string {
+	parts := strings.SplitN(content, "## Transcript", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+
+	lines := 0
+	for i, line := range strings.Split(parts[1], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || i == 0 {
+			continue
+		}
+		if !transcriptLineRegex.MatchString(line) {
+			return fmt.Sprintf("unparseable transcript line %q", truncate(line, 60))
+		}
+		lines++
+	}
+
+	entries := ExtractTranscriptFromMarkdown(content)
+	if len(entries) == 0 {
+		return "transcript section is empty"
+	}
+	if len(entries) != lines {
+		return fmt.Sprintf("transcript has %d lines but only %d entries parse", lines, len(entries))
+	}
+
+	return ""
+}
+
+// isConflictCopy reports whether relPath looks like a copy created by a sync
+// tool. Numbered copies only count when the original file also exists.
+func isConflictCopy(relPath string, existing map[string]bool) bool {
+	name := filepath.Base(relPath)
+	if conflictCopyRegex.MatchString(name) {
+		return true
+	}
+	if m := numberedCopyRegex.FindStringSubmatch(name); m != nil {
+		original := filepath.Join(filepath.Dir(relPath), m[1]+".md")
+		return existing[original]
+	}
+	return false
+}
+
+// truncate shortens s to at most n characters, adding an ellipsis when cut.
+// It never splits a multi-byte character.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
@@ -0,0 +1,115 @@
+package exporter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestVerify(t *testing.T) {
+	docs := map[string]Document{
+		"doc1": {ID: "doc1", Title: "Kickoff", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Notes"},
+		"doc2": {ID: "doc2", Title: "Retro", CreatedAt: "2026-01-22T10:00:00Z", NotesMarkdown: "Notes"},
+	}
+
+	problemsFor := func(result *VerifyResult, file string) []string {
+		var msgs []string
+		for _, p := range result.Problems {
+			if p.File == file {
+				msgs = append(msgs, p.Message)
+			}
+		}
+		return msgs
+	}
+
+	t.Run("accepts files written by the exporter", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		doc := docs["doc1"]
+		transcript := []TranscriptEntry{{Text: "Hello", Source: "microphone"}, {Text: "Hi", Source: "system"}}
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-21_Kickoff.md"), FormatDocumentMarkdown(&doc, transcript))
+
+		result, err := Verify(tmpDir, docs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Files != 1 || len(result.Problems) != 0 {
+			t.Errorf("Expected 1 clean file, got %d files and %+v", result.Files, result.Problems)
+		}
+	})
+
+	t.Run("reports header and transcript problems", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, "broken.md"), "# Broken\nDate: yesterday\n\n---\n\n## Transcript\n\n**Me:** Fine\n\nhand edited line\n")
+		writeTestFile(t, filepath.Join(tmpDir, "unknown.md"), "# Unknown\nDate: 2026-01-21 10:00\nMeeting ID: gone\n")
+
+		result, err := Verify(tmpDir, docs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		broken := strings.Join(problemsFor(result, "broken.md"), "; ")
+		for _, want := range []string{"missing Meeting ID header", "invalid Date header", "unparseable transcript line"} {
+			if !strings.Contains(broken, want) {
+				t.Errorf("Expected %q in problems, got %q", want, broken)
+			}
+		}
+		unknown := problemsFor(result, "unknown.md")
+		if len(unknown) != 1 || unknown[0] != "meeting ID not found in Granola cache" {
+			t.Errorf("Unexpected problems for unknown.md: %v", unknown)
+		}
+	})
+
+	t.Run("detects duplicates and conflict copies", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		doc := docs["doc2"]
+		content := FormatDocumentMarkdown(&doc, nil)
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-22_Retro.md"), content)
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-22_Retro 2.md"), content)
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-22_Retro.sync-conflict-20260122-101010-ABCDEFG.md"), content)
+
+		result, err := Verify(tmpDir, docs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if msgs := problemsFor(result, "2026-01-22_Retro 2.md"); !strings.Contains(strings.Join(msgs, ";"), "sync-conflict copy") {
+			t.Errorf("Expected numbered copy to be flagged, got %v", msgs)
+		}
+		if msgs := problemsFor(result, "2026-01-22_Retro.sync-conflict-20260122-101010-ABCDEFG.md"); !strings.Contains(strings.Join(msgs, ";"), "sync-conflict copy") {
+			t.Errorf("Expected Syncthing copy to be flagged, got %v", msgs)
+		}
+		if msgs := problemsFor(result, "2026-01-22_Retro.md"); len(msgs) != 1 || !strings.HasPrefix(msgs[0], "duplicate meeting ID") {
+			t.Errorf("Expected original to be flagged only as duplicate, got %v", msgs)
+		}
+	})
+
+	t.Run("cuts long transcript lines between characters", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		line := "x" + strings.Repeat("é", 80)
+		writeTestFile(t, filepath.Join(tmpDir, "accents.md"), "# Accents\nDate: 2026-01-21 10:00\nMeeting ID: doc1\n\n---\n\n## Transcript\n\n"+line+"\n")
+
+		result, err := Verify(tmpDir, docs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		msgs := problemsFor(result, "accents.md")
+		if len(msgs) != 1 || !utf8.ValidString(msgs[0]) || !strings.Contains(msgs[0], "x"+strings.Repeat("é", 59)+"...") {
+			t.Errorf("Expected the line cut after 60 characters, got %q", msgs)
+		}
+	})
+
+	t.Run("does not flag numbered titles without an original", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		doc := Document{ID: "doc1", Title: "Sprint 2", CreatedAt: "2026-01-21T10:00:00Z", NotesMarkdown: "Notes"}
+		writeTestFile(t, filepath.Join(tmpDir, "2026-01-21_Sprint 2.md"), FormatDocumentMarkdown(&doc, nil))
+
+		result, err := Verify(tmpDir, docs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Problems) != 0 {
+			t.Errorf("Expected no problems, got %+v", result.Problems)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log formats supported by New.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configures the logger built by New.
+type Options struct {
+	Level  string
+	Format string
+	// Quiet raises the minimum level to warn regardless of Level.
+	Quiet bool
+}
+
+// New builds a structured logger writing to w.
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Quiet && level < slog.LevelWarn {
+		level = slog.LevelWarn
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	switch opts.Format {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, handlerOpts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, handlerOpts)), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q (expected %q or %q)", opts.Format, FormatText, FormatJSON)
+	}
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level.
+// An empty name means info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unsupported log level %q (expected debug, info, warn or error)", name)
+	}
+}
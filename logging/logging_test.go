@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("writes json records", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, Options{Level: "info", Format: FormatJSON})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		logger.Info("wrote document", "file", "2026-01-21_Test.md")
+
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", buf.String(), err)
+		}
+		if record["msg"] != "wrote document" {
+			t.Errorf("Unexpected msg: %v", record["msg"])
+		}
+		if record["file"] != "2026-01-21_Test.md" {
+			t.Errorf("Unexpected file attribute: %v", record["file"])
+		}
+	})
+
+	t.Run("filters below configured level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, Options{Level: "warn"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		logger.Info("hidden")
+		logger.Warn("shown")
+
+		if strings.Contains(buf.String(), "hidden") {
+			t.Error("Expected info record to be filtered")
+		}
+		if !strings.Contains(buf.String(), "shown") {
+			t.Error("Expected warn record to be written")
+		}
+	})
+
+	t.Run("quiet overrides lower levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, Options{Level: "debug", Quiet: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		logger.Info("hidden")
+
+		if buf.Len() != 0 {
+			t.Errorf("Expected no output in quiet mode, got %q", buf.String())
+		}
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		if _, err := New(&bytes.Buffer{}, Options{Format: "xml"}); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"", slog.LevelInfo},
+		{"INFO", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"error", slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if level != tt.expected {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, level, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default rotation settings used by the LaunchAgent.
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024
+	DefaultMaxAge     = 30 * 24 * time.Hour
+	DefaultMaxBackups = 10
+)
+
+// backupTimeFormat is the timestamp embedded in rotated log filenames.
+const backupTimeFormat = "20060102T150405"
+
+// RotatingFile is an io.WriteCloser that appends to a log file and rotates it
+// when it grows past MaxSize or was last written on an earlier day.
+// Rotated files are gzip-compressed and pruned by MaxAge and MaxBackups.
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	day  string
+
+	// now is overridable in tests
+	now func() time.Time
+}
+
+// NewRotatingFile creates a RotatingFile at path with the default limits.
+// The file is opened lazily on the first write.
+func NewRotatingFile(path string) *RotatingFile {
+	return &RotatingFile{
+		Path:       path,
+		MaxSize:    DefaultMaxSize,
+		MaxAge:     DefaultMaxAge,
+		MaxBackups: DefaultMaxBackups,
+		now:        time.Now,
+	}
+}
+
+// Write appends p to the log file, rotating first if needed.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	today := r.now().Format("2006-01-02")
+	if r.size > 0 && (r.size+int64(len(p)) > r.MaxSize || r.day != today) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	r.day = today
+	return n, err
+}
+
+// Close closes the underlying file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open opens the current log file for appending, creating its directory.
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = f
+	r.size = info.Size()
+	r.day = info.ModTime().Format("2006-01-02")
+	return nil
+}
+
+// rotate moves the current file aside, compresses it, prunes old backups and
+// reopens a fresh file.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	r.file = nil
+
+	ext := filepath.Ext(r.Path)
+	base := strings.TrimSuffix(r.Path, ext)
+	backup := fmt.Sprintf("%s-%s%s", base, r.now().Format(backupTimeFormat), ext)
+
+	if err := os.Rename(r.Path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := compressFile(backup); err != nil {
+		return err
+	}
+	if err := r.prune(); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+// Backups returns rotated log files for path, oldest first.
+func Backups(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+	// Timestamps sort lexically
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// prune removes backups older than MaxAge and beyond MaxBackups.
+func (r *RotatingFile) prune() error {
+	backups, err := Backups(r.Path)
+	if err != nil {
+		return fmt.Errorf("failed to list log backups: %w", err)
+	}
+
+	cutoff := r.now().Add(-r.MaxAge)
+	var keep []string
+	for _, b := range backups {
+		info, err := os.Stat(b)
+		if err == nil && r.MaxAge > 0 && info.ModTime().Before(cutoff) {
+			os.Remove(b)
+			continue
+		}
+		keep = append(keep, b)
+	}
+
+	if r.MaxBackups > 0 && len(keep) > r.MaxBackups {
+		for _, b := range keep[:len(keep)-r.MaxBackups] {
+			os.Remove(b)
+		}
+	}
+
+	return nil
+}
+
+// compressFile gzips path to path.gz and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open rotated log: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create compressed log: %w", err)
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to compress log: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to compress log: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to compress log: %w", err)
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRotatingFile(t *testing.T, now *time.Time) *RotatingFile {
+	t.Helper()
+	r := NewRotatingFile(filepath.Join(t.TempDir(), "logs", "granary.log"))
+	r.now = func() time.Time { return *now }
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("creates directory and appends", func(t *testing.T) {
+		now := time.Date(2026, 1, 21, 10, 0, 0, 0, time.UTC)
+		r := newTestRotatingFile(t, &now)
+
+		if _, err := r.Write([]byte("first\n")); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := r.Write([]byte("second\n")); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		content, err := os.ReadFile(r.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "first\nsecond\n" {
+			t.Errorf("Unexpected content: %q", content)
+		}
+	})
+
+	t.Run("rotates and compresses when size exceeded", func(t *testing.T) {
+		now := time.Date(2026, 1, 21, 10, 0, 0, 0, time.UTC)
+		r := newTestRotatingFile(t, &now)
+		r.MaxSize = 10
+
+		r.Write([]byte("0123456789\n"))
+		now = now.Add(time.Second)
+		r.Write([]byte("next\n"))
+
+		backups, err := Backups(r.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+			t.Fatalf("Expected one compressed backup, got %v", backups)
+		}
+
+		f, err := os.Open(backups[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rotated, _ := io.ReadAll(gz)
+		if string(rotated) != "0123456789\n" {
+			t.Errorf("Unexpected rotated content: %q", rotated)
+		}
+
+		current, _ := os.ReadFile(r.Path)
+		if string(current) != "next\n" {
+			t.Errorf("Unexpected current content: %q", current)
+		}
+	})
+
+	t.Run("rotates on a new day", func(t *testing.T) {
+		now := time.Date(2026, 1, 21, 23, 0, 0, 0, time.UTC)
+		r := newTestRotatingFile(t, &now)
+
+		r.Write([]byte("yesterday\n"))
+		now = now.Add(2 * time.Hour)
+		r.Write([]byte("today\n"))
+
+		backups, _ := Backups(r.Path)
+		if len(backups) != 1 {
+			t.Errorf("Expected 1 backup after day change, got %d", len(backups))
+		}
+	})
+
+	t.Run("keeps at most MaxBackups", func(t *testing.T) {
+		now := time.Date(2026, 1, 21, 10, 0, 0, 0, time.UTC)
+		r := newTestRotatingFile(t, &now)
+		r.MaxSize = 1
+		r.MaxBackups = 2
+
+		for i := 0; i < 5; i++ {
+			r.Write([]byte("line\n"))
+			now = now.Add(time.Second)
+		}
+
+		backups, _ := Backups(r.Path)
+		if len(backups) != 2 {
+			t.Errorf("Expected 2 backups, got %d: %v", len(backups), backups)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// TailOptions configures Tail.
+type TailOptions struct {
+	// Follow keeps reading as new records are appended, across rotations.
+	Follow bool
+	// ErrorsOnly limits output to records at error level.
+	ErrorsOnly bool
+	// PollInterval is how often to check for new data when following.
+	PollInterval time.Duration
+}
+
+// Tail copies log records from path to w. When following, it returns once
+// ctx is cancelled.
+func Tail(ctx context.Context, path string, w io.Writer, opts TailOptions) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 500 * time.Millisecond
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { f.Close() }()
+
+	reader := bufio.NewReader(f)
+	var partial string
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err == nil {
+			line = partial + line
+			partial = ""
+			if !opts.ErrorsOnly || IsErrorRecord(line) {
+				if _, err := io.WriteString(w, line); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read log file: %w", err)
+		}
+
+		// Hold on to an incomplete trailing line until the rest is written
+		partial += line
+		if !opts.Follow {
+			if partial != "" && (!opts.ErrorsOnly || IsErrorRecord(partial)) {
+				_, err := io.WriteString(w, partial+"\n")
+				return err
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.PollInterval):
+		}
+
+		if rotated(f, path) {
+			next, err := os.Open(path)
+			if err != nil {
+				// The new file may not exist yet; try again on the next poll
+				continue
+			}
+
+			// Drain anything written to the old file before it was rotated
+			rest, _ := io.ReadAll(reader)
+			for _, line := range strings.SplitAfter(partial+string(rest), "\n") {
+				if line != "" && (!opts.ErrorsOnly || IsErrorRecord(line)) {
+					if _, err := io.WriteString(w, line); err != nil {
+						next.Close()
+						return err
+					}
+				}
+			}
+
+			f.Close()
+			f = next
+			reader = bufio.NewReader(f)
+			partial = ""
+		}
+	}
+}
+
+// rotated reports whether path no longer refers to the open file.
+func rotated(f *os.File, path string) bool {
+	current, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	latest, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(current, latest)
+}
+
+// IsErrorRecord reports whether a text or JSON log line is at error level.
+func IsErrorRecord(line string) bool {
+	return strings.Contains(line, "level=ERROR") || strings.Contains(line, `"level":"ERROR"`)
+}
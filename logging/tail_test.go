@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTail(t *testing.T) {
+	logs := `{"level":"INFO","msg":"parsed cache"}
+{"level":"ERROR","msg":"failed to export document"}
+time=2026-01-21T10:00:00Z level=ERROR msg="failed to write"
+`
+
+	t.Run("prints existing records", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "granary.log")
+		os.WriteFile(path, []byte(logs), 0644)
+
+		var out bytes.Buffer
+		if err := Tail(context.Background(), path, &out, TailOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if out.String() != logs {
+			t.Errorf("Unexpected output: %q", out.String())
+		}
+	})
+
+	t.Run("filters to errors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "granary.log")
+		os.WriteFile(path, []byte(logs), 0644)
+
+		var out bytes.Buffer
+		if err := Tail(context.Background(), path, &out, TailOptions{ErrorsOnly: true}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Contains(out.String(), "parsed cache") {
+			t.Error("Expected info record to be filtered out")
+		}
+		if strings.Count(out.String(), "\n") != 2 {
+			t.Errorf("Expected 2 error records, got %q", out.String())
+		}
+	})
+
+	t.Run("follows appended records across rotation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "granary.log")
+		os.WriteFile(path, []byte("first\n"), 0644)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var out syncBuffer
+		done := make(chan error, 1)
+		go func() {
+			done <- Tail(ctx, path, &out, TailOptions{Follow: true, PollInterval: 5 * time.Millisecond})
+		}()
+
+		waitFor(t, func() bool { return out.String() == "first\n" })
+
+		f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		f.WriteString("second\n")
+		f.Close()
+		waitFor(t, func() bool { return strings.Contains(out.String(), "second\n") })
+
+		os.Rename(path, path+".1")
+		os.WriteFile(path, []byte("third\n"), 0644)
+		waitFor(t, func() bool { return strings.Contains(out.String(), "third\n") })
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for condition")
+}
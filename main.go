@@ -1,39 +1,263 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wassimk/granary/exporter"
+	"github.com/wassimk/granary/logging"
+	"github.com/wassimk/granary/server"
 	"github.com/wassimk/granary/service"
+	"github.com/wassimk/granary/site"
+	"github.com/wassimk/granary/watch"
 )
 
 // version is set at build time via ldflags
 var version = "dev"
 
+var (
+	// logger is configured from the persistent --log-* flags before any command runs
+	logger = slog.Default()
+	quiet  bool
+)
+
 func main() {
+	var logOpts logging.Options
+	var logFile string
 	rootCmd := &cobra.Command{
 		Use:   "granary",
 		Short: "Export Granola meeting notes and transcripts to markdown",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var w io.Writer = os.Stderr
+			if logFile != "" {
+				w = logging.NewRotatingFile(logFile)
+			}
+			l, err := logging.New(w, logOpts)
+			if err != nil {
+				return err
+			}
+			logger = l
+			quiet = logOpts.Quiet
+			return nil
+		},
 	}
+	rootCmd.PersistentFlags().StringVar(&logOpts.Level, "log-level", "info", "Log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logOpts.Format, "log-format", logging.FormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Write logs to this file with size and age based rotation instead of stderr")
+	rootCmd.PersistentFlags().BoolVarP(&logOpts.Quiet, "quiet", "q", false, "Only log warnings and errors, and omit the text summary")
 
 	// run
-	var outputDir string
+	var runOpts runOptions
 	runCmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run the export",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if outputDir == "" {
-				outputDir = exporter.DefaultOutputDir()
+			if err := exporter.ValidateReportFormat(runOpts.reportFormat); err != nil {
+				return err
+			}
+			if err := exporter.ValidateNotes(runOpts.format.Notes); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if runOpts.outputDir == "" {
+				runOpts.outputDir = exporter.DefaultOutputDir()
 			}
-			return runExport(outputDir)
+			return runExport(runOpts)
 		},
 	}
-	runCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Custom output directory (default: ~/.local/share/granola-transcripts)")
+	runCmd.Flags().StringVarP(&runOpts.outputDir, "output-dir", "o", "", "Custom output directory (default: ~/.local/share/granola-transcripts)")
+	runCmd.Flags().IntVarP(&runOpts.jobs, "jobs", "j", 0, "Number of documents to export concurrently (default: one per CPU)")
+	runCmd.Flags().BoolVar(&runOpts.force, "force", false, "Export even if the cache hasn't changed since the last run")
+	runCmd.Flags().IntVar(&runOpts.load.Attempts, "load-attempts", exporter.DefaultLoadAttempts, "Times to try reading the cache while Granola is still writing it")
+	runCmd.Flags().BoolVar(&runOpts.load.Snapshot, "snapshot", false, "Copy the cache to a temporary file before parsing it")
+	runCmd.Flags().StringVar(&runOpts.format.Notes, "notes", exporter.NotesBoth, "Notes sections to write: both, ai or mine")
+	runCmd.Flags().BoolVar(&runOpts.folders, "folders", false, "Mirror Granola folders as directories in the output")
+	runCmd.Flags().BoolVar(&runOpts.seriesIndex, "series-index", false, "Write an index page for each recurring meeting series")
+	runCmd.Flags().BoolVar(&runOpts.index, "index", false, "Write INDEX.md listing every exported meeting")
+	runCmd.Flags().BoolVar(&runOpts.byMonth, "by-month", false, "Write meetings into a directory per month")
+	runCmd.Flags().BoolVar(&runOpts.format.FrontMatter, "front-matter", false, "Start each file with YAML front matter holding the meeting's metadata")
+	runCmd.Flags().BoolVar(&runOpts.format.MergeTurns, "merge-turns", false, "Merge consecutive transcript lines from the same speaker into one paragraph")
+	runCmd.Flags().DurationVar(&runOpts.format.MergeGap, "merge-gap", exporter.DefaultMergeGap, "Longest pause within a merged speaker turn (0 for no limit)")
+	runCmd.Flags().BoolVar(&runOpts.format.Stats, "stats", false, "Add talk time and word counts per speaker to each file")
+	runCmd.Flags().StringVar(&runOpts.reportFormat, "report", exporter.ReportText, "Report format: text or json")
+	runCmd.Flags().StringVar(&runOpts.reportFile, "report-file", "", "Write the report to this file instead of stdout")
 	rootCmd.AddCommand(runCmd)
 
+	// watch
+	var watchRunOpts runOptions
+	var watchOpts watch.Options
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Export whenever Granola updates its cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := exporter.ValidateNotes(watchRunOpts.format.Notes); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if watchRunOpts.outputDir == "" {
+				watchRunOpts.outputDir = exporter.DefaultOutputDir()
+			}
+			watchRunOpts.reportFormat = exporter.ReportText
+			return runWatch(watchRunOpts, watchOpts)
+		},
+	}
+	watchCmd.Flags().StringVarP(&watchRunOpts.outputDir, "output-dir", "o", "", "Custom output directory (default: ~/.local/share/granola-transcripts)")
+	watchCmd.Flags().IntVarP(&watchRunOpts.jobs, "jobs", "j", 0, "Number of documents to export concurrently (default: one per CPU)")
+	watchCmd.Flags().BoolVar(&watchRunOpts.load.Snapshot, "snapshot", false, "Copy the cache to a temporary file before parsing it")
+	watchCmd.Flags().StringVar(&watchRunOpts.format.Notes, "notes", exporter.NotesBoth, "Notes sections to write: both, ai or mine")
+	watchCmd.Flags().BoolVar(&watchRunOpts.folders, "folders", false, "Mirror Granola folders as directories in the output")
+	watchCmd.Flags().BoolVar(&watchRunOpts.seriesIndex, "series-index", false, "Write an index page for each recurring meeting series")
+	watchCmd.Flags().BoolVar(&watchRunOpts.index, "index", false, "Write INDEX.md listing every exported meeting")
+	watchCmd.Flags().BoolVar(&watchRunOpts.byMonth, "by-month", false, "Write meetings into a directory per month")
+	watchCmd.Flags().BoolVar(&watchRunOpts.format.FrontMatter, "front-matter", false, "Start each file with YAML front matter holding the meeting's metadata")
+	watchCmd.Flags().BoolVar(&watchRunOpts.format.MergeTurns, "merge-turns", false, "Merge consecutive transcript lines from the same speaker into one paragraph")
+	watchCmd.Flags().DurationVar(&watchRunOpts.format.MergeGap, "merge-gap", exporter.DefaultMergeGap, "Longest pause within a merged speaker turn (0 for no limit)")
+	watchCmd.Flags().BoolVar(&watchRunOpts.format.Stats, "stats", false, "Add talk time and word counts per speaker to each file")
+	watchCmd.Flags().DurationVar(&watchOpts.Debounce, "debounce", watch.DefaultDebounce, "How long cache writes must pause before exporting")
+	watchCmd.Flags().BoolVar(&watchOpts.Poll, "poll", false, "Poll the cache file instead of using filesystem notifications")
+	rootCmd.AddCommand(watchCmd)
+
+	// prune
+	var pruneOutputDir string
+	var pruneConfirm bool
+	var pruneOpts exporter.PruneOptions
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Archive or delete exported files for meetings deleted in Granola",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if pruneOutputDir == "" {
+				pruneOutputDir = exporter.DefaultOutputDir()
+			}
+			return runPrune(pruneOutputDir, pruneConfirm, pruneOpts)
+		},
+	}
+	pruneCmd.Flags().StringVarP(&pruneOutputDir, "output-dir", "o", "", "Custom output directory (default: ~/.local/share/granola-transcripts)")
+	pruneCmd.Flags().BoolVar(&pruneConfirm, "confirm", false, "Prune the listed files instead of only listing them")
+	pruneCmd.Flags().BoolVar(&pruneOpts.Delete, "delete", false, "Delete files instead of moving them to the archive folder")
+	pruneCmd.Flags().BoolVar(&pruneOpts.Force, "force", false, "Also prune files that contain a preserved transcript")
+	rootCmd.AddCommand(pruneCmd)
+
+	// verify
+	var verifyOutputDir string
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check exported files for missing headers, unknown meetings and corrupted transcripts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if verifyOutputDir == "" {
+				verifyOutputDir = exporter.DefaultOutputDir()
+			}
+			return runVerify(verifyOutputDir)
+		},
+	}
+	verifyCmd.Flags().StringVarP(&verifyOutputDir, "output-dir", "o", "", "Custom output directory (default: ~/.local/share/granola-transcripts)")
+	rootCmd.AddCommand(verifyCmd)
+
+	// doctor
+	var doctorCache string
+	var doctorJSON bool
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Describe the Granola cache and flag formats granary may not understand",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runDoctor(doctorCache, doctorJSON)
+		},
+	}
+	doctorCmd.Flags().StringVar(&doctorCache, "cache", "", "Cache file to inspect (default: the latest cache-v*.json)")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Print the diagnosis as JSON")
+	rootCmd.AddCommand(doctorCmd)
+
+	// stats
+	var statsOutputDir, statsSince, statsUntil string
+	var statsJSON bool
+	var statsOpts exporter.StatsOptions
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize meetings per week, meeting hours, talk share and transcript coverage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if statsOpts.Since, err = parseDay("since", statsSince); err != nil {
+				return err
+			}
+			if statsOpts.Until, err = parseDay("until", statsUntil); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if statsOutputDir == "" {
+				statsOutputDir = exporter.DefaultOutputDir()
+			}
+			return runStats(statsOutputDir, statsOpts, statsJSON)
+		},
+	}
+	statsCmd.Flags().StringVarP(&statsOutputDir, "output-dir", "o", "", "Custom output directory (default: ~/.local/share/granola-transcripts)")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Only count meetings on or after this date (YYYY-MM-DD)")
+	statsCmd.Flags().StringVar(&statsUntil, "until", "", "Only count meetings on or before this date (YYYY-MM-DD)")
+	statsCmd.Flags().IntVar(&statsOpts.Top, "top", exporter.DefaultStatsTop, "Number of most frequent meetings to list")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the statistics as JSON")
+	rootCmd.AddCommand(statsCmd)
+
+	// site
+	siteCmd := &cobra.Command{
+		Use:   "site",
+		Short: "Build a static HTML site of the meeting archive",
+	}
+	var siteOutputDir string
+	var siteOpts site.Options
+	siteBuildCmd := &cobra.Command{
+		Use:   "build",
+		Short: "Render every meeting to HTML with indexes by date and series and offline search",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if siteOutputDir == "" {
+				siteOutputDir = exporter.DefaultOutputDir()
+			}
+			return runSiteBuild(siteOutputDir, siteOpts)
+		},
+	}
+	siteBuildCmd.Flags().StringVarP(&siteOutputDir, "output-dir", "o", "", "Export directory to read preserved meetings from (default: ~/.local/share/granola-transcripts)")
+	siteBuildCmd.Flags().StringVar(&siteOpts.OutDir, "out", "", "Directory to write the site to")
+	siteBuildCmd.Flags().StringVar(&siteOpts.Title, "title", site.DefaultTitle, "Title shown at the top of every page")
+	siteBuildCmd.MarkFlagRequired("out")
+	siteCmd.AddCommand(siteBuildCmd)
+	rootCmd.AddCommand(siteCmd)
+
+	// serve
+	var serveAddr, serveOutputDir string
+	var serveOpts server.Options
+	var serveWatchOpts watch.Options
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve meetings in a local web UI and JSON API, reloading when Granola updates its cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if serveOutputDir == "" {
+				serveOutputDir = exporter.DefaultOutputDir()
+			}
+			return runServe(serveAddr, serveOutputDir, serveOpts, serveWatchOpts)
+		},
+	}
+	serveCmd.Flags().StringVar(&serveAddr, "addr", server.DefaultAddr, "Address to listen on")
+	serveCmd.Flags().StringVarP(&serveOutputDir, "output-dir", "o", "", "Export directory to read preserved meetings from (default: ~/.local/share/granola-transcripts)")
+	serveCmd.Flags().StringVar(&serveOpts.Title, "title", site.DefaultTitle, "Title shown at the top of every page")
+	serveCmd.Flags().DurationVar(&serveWatchOpts.Debounce, "debounce", watch.DefaultDebounce, "How long cache writes must pause before reloading")
+	serveCmd.Flags().BoolVar(&serveWatchOpts.Poll, "poll", false, "Poll the cache file instead of using filesystem notifications")
+	rootCmd.AddCommand(serveCmd)
+
 	// install
 	var force bool
 	installCmd := &cobra.Command{
@@ -73,6 +297,7 @@ func main() {
 			fmt.Printf("Label:     %s\n", label)
 			fmt.Printf("Plist:     %s\n", plist)
 			fmt.Printf("Logs:      %s\n", logDir)
+			fmt.Printf("Log file:  %s\n", service.LogFile())
 			fmt.Printf("Installed: %v\n", installed)
 			fmt.Printf("Running:   %v\n", running)
 			return nil
@@ -80,6 +305,25 @@ func main() {
 	}
 	rootCmd.AddCommand(statusCmd)
 
+	// logs
+	var follow, errorsOnly bool
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show logs written by the LaunchAgent",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return logging.Tail(ctx, service.LogFile(), os.Stdout, logging.TailOptions{
+				Follow:     follow,
+				ErrorsOnly: errorsOnly,
+			})
+		},
+	}
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new log records as they are written")
+	logsCmd.Flags().BoolVar(&errorsOnly, "errors", false, "Only show error records")
+	rootCmd.AddCommand(logsCmd)
+
 	// version
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -90,43 +334,484 @@ func main() {
 	}
 	rootCmd.AddCommand(versionCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	// Errors are logged rather than printed by cobra, so they reach the log
+	// file of the LaunchAgent, whose stderr is discarded
+	rootCmd.SilenceErrors = true
+	if err := execute(rootCmd); err != nil {
+		logger.Error("granary failed", "error", err)
 		os.Exit(1)
 	}
 }
 
-func runExport(outputDir string) error {
-	cachePath, err := exporter.FindCacheFile()
+// execute runs the command line, turning a panic into an error that
+// carries its stack so it is logged like any other failure.
+func execute(rootCmd *cobra.Command) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return rootCmd.Execute()
+}
+
+// runOptions holds the flags of the run command.
+type runOptions struct {
+	outputDir    string
+	jobs         int
+	force        bool
+	reportFormat string
+	reportFile   string
+	load         exporter.LoadOptions
+	format       exporter.FormatOptions
+	folders      bool
+	seriesIndex  bool
+	index        bool
+	byMonth      bool
+}
+
+func runExport(opts runOptions) error {
+	start := time.Now()
+
+	cache, err := findCache()
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Loading cache from: %s\n", cachePath)
+	// Skip parsing entirely when the cache hasn't changed since the last run
+	exportState, err := exporter.LoadExportState(opts.outputDir)
+	if err != nil {
+		return err
+	}
+	fingerprint, unchanged, err := exporter.CheckCacheFingerprint(cache.Path, exportState.Cache)
+	if err != nil {
+		return err
+	}
+	if unchanged && exportState.Version == version && exportState.Format == opts.format &&
+		exportState.Folders == opts.folders && exportState.SeriesIndex == opts.seriesIndex && exportState.Index == opts.index &&
+		exportState.ByMonth == opts.byMonth && !opts.force {
+		logger.Info("cache unchanged since last run", "path", cache.Path)
+		if *exportState.Cache != fingerprint {
+			// Touched but identical; remember the new mtime to avoid rehashing next time
+			exportState.Cache = &fingerprint
+			if err := exportState.Save(opts.outputDir); err != nil {
+				logger.Warn("failed to update export state", "error", err)
+			}
+		}
+		result := &exporter.ExportResult{CacheUnchanged: true}
+		return writeReport(result, opts.outputDir, cache, time.Since(start), opts.reportFormat, opts.reportFile)
+	}
+
+	state, err := parseCache(cache, opts.load)
+	if err != nil {
+		return err
+	}
+
+	exp := exporter.NewExporter(opts.outputDir)
+	exp.Logger = logger
+	exp.Jobs = opts.jobs
+	exp.Version = version
+	exp.State = exportState
+	exp.Format = opts.format
+	exp.MirrorFolders = opts.folders
+	exp.SeriesIndex = opts.seriesIndex
+	exp.Index = opts.index
+	exp.ByMonth = opts.byMonth
+	result, err := exp.Export(state)
+	if err != nil {
+		return err
+	}
+
+	if err := writeReport(result, opts.outputDir, cache, time.Since(start), opts.reportFormat, opts.reportFile); err != nil {
+		return err
+	}
+
+	logger.Info("export finished",
+		"written", result.Written,
+		"skipped", result.Skipped,
+		"empty", result.Empty,
+		"errors", len(result.Errors),
+		"duration", time.Since(start).Round(time.Millisecond),
+	)
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d document(s) failed to export", len(result.Errors))
+	}
+	if len(result.IndexErrors) > 0 {
+		return fmt.Errorf("failed to update index pages: %s", strings.Join(result.IndexErrors, "; "))
+	}
+
+	// Only remember the cache once every document made it out, so failures are retried
+	exportState.Cache = &fingerprint
+	if err := exportState.Save(opts.outputDir); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runWatch exports once, then again every time the Granola cache settles
+// after a change, until interrupted.
+func runWatch(opts runOptions, watchOpts watch.Options) error {
+	granolaDir, err := exporter.GranolaDir()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := runExport(opts); err != nil {
+		logger.Error("export failed", "error", err)
+	}
+
+	watchOpts.Dir = granolaDir
+	watchOpts.Logger = logger
+	return watch.Run(ctx, watchOpts, func(path string) error {
+		logger.Info("cache changed", "path", path)
+		return runExport(opts)
+	})
+}
+
+// findCache locates the latest Granola cache file.
+func findCache() (exporter.CacheInfo, error) {
+	cachePath, err := exporter.FindCacheFile()
+	if err != nil {
+		return exporter.CacheInfo{}, err
+	}
 
 	cacheSize, err := exporter.GetCacheSize(cachePath)
 	if err != nil {
-		return fmt.Errorf("failed to get cache size: %w", err)
+		return exporter.CacheInfo{}, fmt.Errorf("failed to get cache size: %w", err)
+	}
+
+	return exporter.CacheInfo{
+		Path:      cachePath,
+		Version:   exporter.CacheVersion(cachePath),
+		SizeBytes: cacheSize,
+	}, nil
+}
+
+// parseCache parses the cache file described by cache, retrying while
+// Granola is still writing it.
+func parseCache(cache exporter.CacheInfo, opts exporter.LoadOptions) (*exporter.CacheState, error) {
+	logger.Info("loading cache", "path", cache.Path, "size_mb", fmt.Sprintf("%.1f", float64(cache.SizeBytes)/1024.0/1024.0))
+
+	opts.Logger = logger
+	state, err := exporter.LoadCacheWithRetry(cache.Path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("parsed cache",
+		"documents", len(state.Documents),
+		"shared_documents", len(state.SharedDocuments),
+		"transcripts", len(state.Transcripts),
+	)
+	return state, nil
+}
+
+// loadCache finds and parses the latest Granola cache.
+func loadCache() (*exporter.CacheState, exporter.CacheInfo, error) {
+	cache, err := findCache()
+	if err != nil {
+		return nil, exporter.CacheInfo{}, err
+	}
+
+	state, err := parseCache(cache, exporter.LoadOptions{})
+	if err != nil {
+		return nil, exporter.CacheInfo{}, err
+	}
+	return state, cache, nil
+}
+
+// runPrune lists exported files whose meetings were deleted in Granola and,
+// when confirmed, archives or deletes them.
+func runPrune(outputDir string, confirm bool, opts exporter.PruneOptions) error {
+	state, _, err := loadCache()
+	if err != nil {
+		return err
+	}
+
+	files, err := exporter.ScanExports(outputDir)
+	if err != nil {
+		return err
+	}
+
+	orphans := exporter.FindOrphans(files, state.AllDocuments())
+	if len(orphans) == 0 {
+		fmt.Println("No orphaned files found.")
+		return nil
+	}
+
+	if !confirm {
+		fmt.Printf("Found %d file(s) for meetings no longer in Granola:\n", len(orphans))
+		for _, f := range orphans {
+			note := ""
+			if f.HasTranscript && !opts.Force {
+				note = " (has preserved transcript, kept unless --force)"
+			}
+			fmt.Printf("  %s%s\n", f.RelPath, note)
+		}
+		fmt.Println("\nRun again with --confirm to prune them.")
+		return nil
+	}
+
+	result, err := exporter.Prune(outputDir, orphans, opts)
+	for _, f := range result.Archived {
+		fmt.Printf("Archived: %s\n", f)
+	}
+	for _, f := range result.Deleted {
+		fmt.Printf("Deleted:  %s\n", f)
+	}
+	for _, f := range result.Unlinked {
+		fmt.Printf("Unlinked: %s\n", f)
+	}
+	for _, f := range result.Protected {
+		fmt.Printf("Kept:     %s (has preserved transcript, use --force)\n", f)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(result.Archived) > 0 {
+		fmt.Printf("\nArchived files moved to: %s\n", filepath.Join(outputDir, exporter.ArchiveDirName))
+	}
+	return nil
+}
+
+// runVerify checks every exported file and fails if any problems are found.
+func runVerify(outputDir string) error {
+	state, _, err := loadCache()
+	if err != nil {
+		return err
+	}
+
+	result, err := exporter.Verify(outputDir, state.AllDocuments())
+	if err != nil {
+		return err
+	}
+
+	for _, p := range result.Problems {
+		fmt.Printf("%s: %s\n", p.File, p.Message)
+	}
+
+	if len(result.Problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in %d file(s)", len(result.Problems), result.Files)
+	}
+
+	fmt.Printf("Verified %d file(s), no problems found.\n", result.Files)
+	return nil
+}
+
+// runDoctor prints a diagnosis of the cache file at cachePath, or of the
+// latest cache file when cachePath is empty.
+func runDoctor(cachePath string, asJSON bool) error {
+	if cachePath == "" {
+		path, err := exporter.FindCacheFile()
+		if err != nil {
+			return err
+		}
+		cachePath = path
+	}
+
+	d, err := exporter.DiagnoseCache(cachePath)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+
+	fmt.Printf("Cache:       %s\n", d.Path)
+	fmt.Printf("Version:     v%d (latest tested: v%d)\n", d.Version, exporter.LatestTestedCacheVersion)
+	fmt.Printf("Format:      %s\n", d.Format)
+	fmt.Printf("Size:        %.1f MB\n", float64(d.SizeBytes)/1024.0/1024.0)
+	fmt.Printf("State keys:  %s\n", listOrNone(d.StateKeys))
+	fmt.Printf("Documents:   %d owned, %d shared, %d deleted, %d exportable\n", d.Documents, d.SharedDocuments, d.Deleted, d.Exportable)
+	fmt.Printf("Transcripts: %d (%d entries)\n", d.Transcripts, d.TranscriptEntries)
+	fmt.Printf("Unknown document fields:   %s\n", listOrNone(d.UnknownDocumentFields))
+	fmt.Printf("Unknown transcript fields: %s\n", listOrNone(d.UnknownTranscriptFields))
+
+	for _, w := range d.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	if len(d.Warnings) == 0 {
+		fmt.Println("No problems found.")
+	}
+	return nil
+}
+
+// runStats prints statistics over every meeting in the cache and the
+// exported files in outputDir.
+func runStats(outputDir string, opts exporter.StatsOptions, asJSON bool) error {
+	state, _, err := loadCache()
+	if err != nil {
+		return err
 	}
-	cacheSizeMB := float64(cacheSize) / 1024.0 / 1024.0
-	fmt.Printf("Cache size: %.1f MB\n\n", cacheSizeMB)
 
-	fmt.Println("Parsing cache...")
-	state, err := exporter.LoadCache(cachePath)
+	meetings, err := exporter.CollectMeetings(state, outputDir)
 	if err != nil {
 		return err
 	}
+	stats := exporter.ComputeArchiveStats(meetings, opts)
 
-	fmt.Printf("Found %d documents\n", len(state.Documents))
-	fmt.Printf("Found %d shared documents\n", len(state.SharedDocuments))
-	fmt.Printf("Found %d transcripts\n\n", len(state.Transcripts))
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	stats.WriteTable(os.Stdout)
+	return nil
+}
 
-	exp := exporter.NewExporter(outputDir)
-	result, err := exp.Export(state, true)
+// runSiteBuild renders every meeting in the cache and the exported files in
+// outputDir as a static site.
+func runSiteBuild(outputDir string, opts site.Options) error {
+	state, _, err := loadCache()
 	if err != nil {
 		return err
 	}
 
-	result.PrintSummary(outputDir)
+	meetings, err := exporter.CollectMeetings(state, outputDir)
+	if err != nil {
+		return err
+	}
 
+	opts.Logger = logger
+	result, err := site.Build(meetings, opts)
+	if err != nil {
+		return err
+	}
+
+	if !quiet {
+		fmt.Printf("Built %d meeting page(s) and %d series in %s\n", result.Meetings, result.Series, opts.OutDir)
+		if result.Removed > 0 {
+			fmt.Printf("Removed %d page(s) for meetings no longer in the archive\n", result.Removed)
+		}
+		fmt.Printf("Open %s in a browser to view it.\n", filepath.Join(opts.OutDir, "index.html"))
+	}
+	return nil
+}
+
+// runServe serves every meeting in the cache and the exported files in
+// outputDir on addr until interrupted, reloading them whenever the Granola
+// cache settles after a change.
+func runServe(addr, outputDir string, opts server.Options, watchOpts watch.Options) error {
+	granolaDir, err := exporter.GranolaDir()
+	if err != nil {
+		return err
+	}
+
+	opts.Addr = addr
+	opts.Logger = logger
+	srv := server.New(opts)
+	load := func() error {
+		state, _, err := loadCache()
+		if err != nil {
+			return err
+		}
+		meetings, err := exporter.CollectMeetings(state, outputDir)
+		if err != nil {
+			return err
+		}
+		return srv.Load(meetings)
+	}
+	if err := load(); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(ln)
+	}()
+	logger.Info("serving meetings", "url", "http://"+ln.Addr().String())
+	if !quiet {
+		fmt.Printf("Serving meetings at http://%s\n", ln.Addr())
+	}
+
+	watchOpts.Dir = granolaDir
+	watchOpts.Logger = logger
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	go func() {
+		err := watch.Run(watchCtx, watchOpts, func(path string) error {
+			logger.Info("cache changed", "path", path)
+			// On failure the previous meetings keep being served until the retry
+			return load()
+		})
+		if err != nil && watchCtx.Err() == nil {
+			logger.Error("watching the cache failed", "error", err)
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// parseDay parses a YYYY-MM-DD flag value as midnight UTC. An empty value
+// returns the zero time.
+func parseDay(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s date %q (expected YYYY-MM-DD)", flag, value)
+	}
+	return t, nil
+}
+
+// listOrNone joins items for display, or returns "none" for an empty list.
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// writeReport writes the export report in the requested format to stdout,
+// or to reportFile when one is given.
+func writeReport(result *exporter.ExportResult, outputDir string, cache exporter.CacheInfo, duration time.Duration, format, reportFile string) error {
+	w := os.Stdout
+	if reportFile != "" {
+		f, err := os.Create(reportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == exporter.ReportJSON {
+		if err := exporter.NewReport(result, outputDir, cache, duration).WriteJSON(w); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		return nil
+	}
+
+	if quiet && reportFile == "" {
+		return nil
+	}
+	result.WriteSummary(w, outputDir)
 	return nil
 }
@@ -0,0 +1,321 @@
+// Package server serves the meeting archive over HTTP: the pages of the
+// static site for people, and a read-only JSON API for tools that would
+// rather not parse the exported files.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wassimk/granary/exporter"
+	"github.com/wassimk/granary/site"
+)
+
+// DefaultAddr is the address granary serve listens on by default. It only
+// accepts connections from the same machine.
+const DefaultAddr = "127.0.0.1:8080"
+
+// Options configures a Server.
+type Options struct {
+	// Addr is the address the server listens on. Besides loopback names and
+	// addresses, requests are only accepted for its host.
+	Addr string
+	// Title is shown at the top of every page.
+	Title string
+	// Logger receives progress messages. A nil Logger discards them.
+	Logger *slog.Logger
+}
+
+// Server answers requests from the meetings it was last loaded with. It is
+// safe to Load new meetings while requests are being served.
+type Server struct {
+	opts Options
+
+	mu   sync.RWMutex
+	data *snapshot
+}
+
+// snapshot is everything a request needs, rebuilt on every Load.
+type snapshot struct {
+	// meetings are sorted newest first.
+	meetings []exporter.Meeting
+	byID     map[string]int
+	// text is each meeting's searchable text, lowercased.
+	text   []string
+	site   *site.Site
+	loaded time.Time
+}
+
+// New returns a Server with no meetings.
+func New(opts Options) *Server {
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	return &Server{
+		opts: opts,
+		data: &snapshot{site: &site.Site{}, loaded: time.Now()},
+	}
+}
+
+// Load replaces the meetings being served. meetings must be sorted by date,
+// as returned by exporter.CollectMeetings. If the site can't be rendered,
+// the previous meetings are kept.
+func (s *Server) Load(meetings []exporter.Meeting) error {
+	rendered, err := site.Render(meetings, s.opts.Title)
+	if err != nil {
+		return err
+	}
+
+	data := &snapshot{
+		meetings: make([]exporter.Meeting, 0, len(meetings)),
+		byID:     make(map[string]int, len(meetings)),
+		text:     make([]string, 0, len(meetings)),
+		site:     rendered,
+		loaded:   time.Now(),
+	}
+	for i := len(meetings) - 1; i >= 0; i-- {
+		m := meetings[i]
+		data.byID[m.ID] = len(data.meetings)
+		data.meetings = append(data.meetings, m)
+		data.text = append(data.text, searchText(m))
+	}
+
+	s.mu.Lock()
+	s.data = data
+	s.mu.Unlock()
+
+	s.opts.Logger.Info("loaded meetings", "meetings", len(meetings))
+	return nil
+}
+
+// snapshot returns the meetings currently being served.
+func (s *Server) snapshot() *snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data
+}
+
+// Handler returns the HTTP handler for the web UI and the JSON API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/meetings", s.listMeetings)
+	mux.HandleFunc("GET /api/meetings/{id}", s.getMeeting)
+	mux.HandleFunc("GET /api/search", s.search)
+	mux.HandleFunc("GET /api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not found")
+	})
+	mux.HandleFunc("GET /", s.serveSite)
+	return s.checkHost(mux)
+}
+
+// checkHost rejects requests whose Host header isn't a loopback name or
+// address, or the host the server listens on. Without it a web page could
+// point a domain it controls at 127.0.0.1 (DNS rebinding) and read meetings
+// through the visitor's browser.
+func (s *Server) checkHost(next http.Handler) http.Handler {
+	allowed := ""
+	if host, _, err := net.SplitHostPort(s.opts.Addr); err == nil {
+		allowed = strings.ToLower(host)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(strings.Trim(host, "[]"))
+
+		ip := net.ParseIP(host)
+		if host == "localhost" || (ip != nil && ip.IsLoopback()) || (host != "" && host == allowed) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		s.opts.Logger.Warn("rejected request for unknown host", "host", r.Host, "path", r.URL.Path)
+		writeError(w, http.StatusForbidden, "host not allowed")
+	})
+}
+
+// MeetingSummary is a meeting as listed by the API.
+type MeetingSummary struct {
+	ID              string    `json:"id"`
+	Title           string    `json:"title"`
+	Date            time.Time `json:"date,omitzero"`
+	HasNotes        bool      `json:"has_notes"`
+	HasTranscript   bool      `json:"has_transcript"`
+	DurationSeconds int64     `json:"duration_seconds,omitempty"`
+	Series          string    `json:"series,omitempty"`
+	// File is the exported markdown file relative to the output directory.
+	File string `json:"file,omitempty"`
+	// URL is the meeting's page in the web UI.
+	URL string `json:"url"`
+}
+
+// MeetingDetail is a single meeting with its notes and transcript.
+type MeetingDetail struct {
+	MeetingSummary
+	Attendees  []exporter.Attendee       `json:"attendees,omitempty"`
+	MyNotes    string                    `json:"my_notes,omitempty"`
+	AINotes    string                    `json:"ai_notes,omitempty"`
+	Stats      *exporter.TranscriptStats `json:"stats,omitempty"`
+	Transcript []TranscriptLine          `json:"transcript,omitempty"`
+}
+
+// TranscriptLine is one entry of a meeting's transcript.
+type TranscriptLine struct {
+	Speaker string `json:"speaker"`
+	Source  string `json:"source"`
+	Start   string `json:"start,omitempty"`
+	End     string `json:"end,omitempty"`
+	Text    string `json:"text"`
+}
+
+// MeetingList is the response of /api/meetings and /api/search.
+type MeetingList struct {
+	// Query is the search query, for /api/search.
+	Query    string           `json:"query,omitempty"`
+	Count    int              `json:"count"`
+	Meetings []MeetingSummary `json:"meetings"`
+}
+
+// listMeetings lists every meeting, newest first.
+func (s *Server) listMeetings(w http.ResponseWriter, r *http.Request) {
+	data := s.snapshot()
+	list := MeetingList{Meetings: make([]MeetingSummary, 0, len(data.meetings))}
+	for _, m := range data.meetings {
+		list.Meetings = append(list.Meetings, data.summary(m))
+	}
+	list.Count = len(list.Meetings)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getMeeting returns one meeting by ID.
+func (s *Server) getMeeting(w http.ResponseWriter, r *http.Request) {
+	data := s.snapshot()
+	i, ok := data.byID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return
+	}
+
+	m := data.meetings[i]
+	detail := MeetingDetail{
+		MeetingSummary: data.summary(m),
+		Attendees:      m.Attendees,
+		MyNotes:        m.MyNotes,
+		AINotes:        m.AINotes,
+		Stats:          m.Stats,
+	}
+	for _, entry := range m.Transcript {
+		text := strings.TrimSpace(entry.Text)
+		if text == "" {
+			continue
+		}
+		detail.Transcript = append(detail.Transcript, TranscriptLine{
+			Speaker: exporter.SourceToSpeaker(entry.Source),
+			Source:  entry.Source,
+			Start:   entry.StartTimestamp,
+			End:     entry.EndTimestamp,
+			Text:    text,
+		})
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// search lists the meetings, newest first, whose title, attendees, notes or
+// transcript contain every word of the q parameter.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		writeError(w, http.StatusBadRequest, "missing search query q")
+		return
+	}
+
+	data := s.snapshot()
+	list := MeetingList{Query: query, Meetings: []MeetingSummary{}}
+	for i, m := range data.meetings {
+		if matchesAll(data.text[i], words) {
+			list.Meetings = append(list.Meetings, data.summary(m))
+		}
+	}
+	list.Count = len(list.Meetings)
+	writeJSON(w, http.StatusOK, list)
+}
+
+// serveSite serves the pages of the rendered site.
+func (s *Server) serveSite(w http.ResponseWriter, r *http.Request) {
+	data := s.snapshot()
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if name == "" || strings.HasSuffix(r.URL.Path, "/") {
+		name = path.Join(name, "index.html")
+	}
+
+	content, ok := data.site.Files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, name, data.loaded, bytes.NewReader(content))
+}
+
+// summary describes m for a meeting list.
+func (data *snapshot) summary(m exporter.Meeting) MeetingSummary {
+	title := m.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	return MeetingSummary{
+		ID:              m.ID,
+		Title:           title,
+		Date:            m.Date,
+		HasNotes:        m.HasNotes,
+		HasTranscript:   m.HasTranscript,
+		DurationSeconds: m.DurationSeconds,
+		Series:          m.Series,
+		File:            m.File,
+		URL:             "/" + data.site.Pages[m.ID],
+	}
+}
+
+// searchText is the lowercased text a search looks through for m.
+func searchText(m exporter.Meeting) string {
+	text := []string{m.Title, m.MyNotes, m.AINotes}
+	for _, a := range m.Attendees {
+		text = append(text, a.Name, a.Email)
+	}
+	for _, entry := range m.Transcript {
+		text = append(text, entry.Text)
+	}
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(text, " ")), " "))
+}
+
+// matchesAll reports whether text contains every word.
+func matchesAll(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
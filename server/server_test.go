@@ -0,0 +1,264 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wassimk/granary/exporter"
+)
+
+func testMeetings() []exporter.Meeting {
+	day := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", s)
+		return t
+	}
+	return []exporter.Meeting{
+		{
+			ID: "sync-1", Title: "Weekly Sync", Date: day("2026-01-19 10:00"), Series: "event:sync",
+			HasNotes: true, AINotes: "## Decisions\n\n- Ship the forecast",
+			Attendees: []exporter.Attendee{{Name: "Ada", Email: "ada@example.com"}},
+		},
+		{
+			ID: "one-off", Title: "Budget review", Date: day("2026-01-20 15:00"),
+			HasTranscript: true,
+			Transcript: []exporter.TranscriptEntry{
+				{Source: "microphone", Text: "Shall we start?", StartTimestamp: "2026-01-20T15:00:01Z"},
+				{Source: "system", Text: "  "},
+				{Source: "system", Text: "Yes, the forecast is ready."},
+			},
+		},
+		{ID: "sync-2", Title: "Weekly Sync", Date: day("2026-02-02 10:00"), Series: "event:sync", HasNotes: true, MyNotes: "Follow up"},
+	}
+}
+
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080"+target, nil))
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Expected a JSON response, got %q", ct)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	s := New(Options{})
+	if err := s.Load(testMeetings()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return s
+}
+
+func TestServerAPI(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	t.Run("lists meetings newest first", func(t *testing.T) {
+		rec := get(t, h, "/api/meetings")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", rec.Code)
+		}
+		var list MeetingList
+		decode(t, rec, &list)
+
+		var ids []string
+		for _, m := range list.Meetings {
+			ids = append(ids, m.ID)
+		}
+		if list.Count != 3 || strings.Join(ids, ",") != "sync-2,one-off,sync-1" {
+			t.Errorf("Unexpected meetings: %d %v", list.Count, ids)
+		}
+		if got := list.Meetings[0].URL; got != "/meetings/sync-2.html" {
+			t.Errorf("Expected the page URL, got %q", got)
+		}
+	})
+
+	t.Run("returns a meeting with its transcript", func(t *testing.T) {
+		rec := get(t, h, "/api/meetings/one-off")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", rec.Code)
+		}
+		var detail MeetingDetail
+		decode(t, rec, &detail)
+
+		if detail.Title != "Budget review" || !detail.HasTranscript {
+			t.Errorf("Unexpected meeting: %+v", detail.MeetingSummary)
+		}
+		if len(detail.Transcript) != 2 {
+			t.Fatalf("Expected blank lines to be dropped, got %+v", detail.Transcript)
+		}
+		first := detail.Transcript[0]
+		if first.Speaker != "Me" || first.Source != "microphone" || first.Start != "2026-01-20T15:00:01Z" {
+			t.Errorf("Unexpected transcript line: %+v", first)
+		}
+	})
+
+	t.Run("returns 404 for unknown meetings", func(t *testing.T) {
+		rec := get(t, h, "/api/meetings/missing")
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected 404, got %d", rec.Code)
+		}
+		var body map[string]string
+		decode(t, rec, &body)
+		if body["error"] == "" {
+			t.Errorf("Expected an error message, got %v", body)
+		}
+	})
+
+	t.Run("searches notes, attendees and transcripts", func(t *testing.T) {
+		tests := []struct {
+			query string
+			want  string
+		}{
+			{"forecast", "one-off,sync-1"},
+			{"FORECAST ready", "one-off"},
+			{"ada@example.com", "sync-1"},
+			{"follow", "sync-2"},
+			{"nothing matches", ""},
+		}
+		for _, tt := range tests {
+			rec := get(t, h, "/api/search?q="+strings.ReplaceAll(tt.query, " ", "+"))
+			var list MeetingList
+			decode(t, rec, &list)
+
+			var ids []string
+			for _, m := range list.Meetings {
+				ids = append(ids, m.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want || list.Query != tt.query {
+				t.Errorf("Search %q returned %q (query %q), want %q", tt.query, got, list.Query, tt.want)
+			}
+		}
+	})
+
+	t.Run("rejects an empty search", func(t *testing.T) {
+		if rec := get(t, h, "/api/search?q=+"); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("is read-only", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080/api/meetings", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected 405, got %d", rec.Code)
+		}
+	})
+
+	t.Run("returns 404 for unknown endpoints", func(t *testing.T) {
+		if rec := get(t, h, "/api/nope"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestServerSite(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/", "text/html", "Weekly Sync"},
+		{"/meetings/one-off.html", "text/html", "Yes, the forecast is ready."},
+		{"/series.html", "text/html", "Weekly Sync"},
+		{"/assets/style.css", "text/css", "body"},
+		{"/search-index.js", "javascript", "granarySearchIndex"},
+	}
+	for _, tt := range tests {
+		rec := get(t, h, tt.path)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", tt.path, rec.Code)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, tt.contentType) {
+			t.Errorf("%s: unexpected content type %q", tt.path, ct)
+		}
+		if body, _ := io.ReadAll(rec.Body); !strings.Contains(string(body), tt.contains) {
+			t.Errorf("%s: expected %q in the page", tt.path, tt.contains)
+		}
+	}
+
+	if rec := get(t, h, "/meetings/missing.html"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing page, got %d", rec.Code)
+	}
+}
+
+func TestServerHost(t *testing.T) {
+	s := New(Options{Addr: "mybox.lan:8080"})
+	if err := s.Load(testMeetings()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := s.Handler()
+
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"127.0.0.1:8080", http.StatusOK},
+		{"localhost:8080", http.StatusOK},
+		{"LOCALHOST", http.StatusOK},
+		{"[::1]:8080", http.StatusOK},
+		{"mybox.lan:8080", http.StatusOK},
+		{"evil.example:8080", http.StatusForbidden},
+		{"evil.example", http.StatusForbidden},
+		{"192.168.1.20:8080", http.StatusForbidden},
+		{"", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/meetings/one-off", nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Host %q: expected %d, got %d", tt.host, tt.want, rec.Code)
+		}
+		if tt.want == http.StatusForbidden && strings.Contains(rec.Body.String(), "forecast") {
+			t.Errorf("Host %q: expected no meeting data in the response", tt.host)
+		}
+	}
+}
+
+func TestServerLoad(t *testing.T) {
+	t.Run("serves nothing before the first load", func(t *testing.T) {
+		h := New(Options{}).Handler()
+		var list MeetingList
+		decode(t, get(t, h, "/api/meetings"), &list)
+		if list.Count != 0 || list.Meetings == nil {
+			t.Errorf("Expected an empty list, got %+v", list)
+		}
+		if rec := get(t, h, "/"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("replaces the meetings being served", func(t *testing.T) {
+		s := newTestServer(t)
+		h := s.Handler()
+		if err := s.Load(testMeetings()[:1]); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var list MeetingList
+		decode(t, get(t, h, "/api/meetings"), &list)
+		if list.Count != 1 || list.Meetings[0].ID != "sync-1" {
+			t.Errorf("Expected only the reloaded meeting, got %+v", list.Meetings)
+		}
+		if rec := get(t, h, "/meetings/one-off.html"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected the removed meeting's page to be gone, got %d", rec.Code)
+		}
+	})
+}
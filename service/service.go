@@ -20,6 +20,11 @@ func LogDir() string {
 	return filepath.Join(home, "Library", "Logs", "granary")
 }
 
+// LogFile returns the path of the log file granary writes when run by the LaunchAgent.
+func LogFile() string {
+	return filepath.Join(LogDir(), "granary.log")
+}
+
 func currentUID() string {
 	out, err := exec.Command("id", "-u").Output()
 	if err != nil {
@@ -28,11 +33,10 @@ func currentUID() string {
 	return strings.TrimSpace(string(out))
 }
 
+// generatePlist returns the LaunchAgent definition. Everything granary logs
+// goes to LogFile, which it rotates itself, so launchd discards stdout and
+// stderr rather than appending them to files that would grow forever.
 func generatePlist(binaryPath string) string {
-	logDir := LogDir()
-	stdoutLog := filepath.Join(logDir, "stdout.log")
-	stderrLog := filepath.Join(logDir, "stderr.log")
-
 	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
 <plist version="1.0">
@@ -43,20 +47,25 @@ func generatePlist(binaryPath string) string {
     <array>
         <string>%s</string>
         <string>run</string>
+        <string>--quiet</string>
+        <string>--log-format</string>
+        <string>json</string>
+        <string>--log-file</string>
+        <string>%s</string>
     </array>
     <key>StartInterval</key>
     <integer>7200</integer>
     <key>StandardOutPath</key>
-    <string>%s</string>
+    <string>/dev/null</string>
     <key>StandardErrorPath</key>
-    <string>%s</string>
+    <string>/dev/null</string>
     <key>EnvironmentVariables</key>
     <dict>
         <key>PATH</key>
         <string>/opt/homebrew/bin:/usr/local/bin:/usr/bin:/bin</string>
     </dict>
 </dict>
-</plist>`, Label, binaryPath, stdoutLog, stderrLog)
+</plist>`, Label, binaryPath, LogFile())
 }
 
 func Install(force bool) error {
@@ -96,7 +105,7 @@ func Install(force bool) error {
 	fmt.Println("LaunchAgent installed and loaded.")
 	fmt.Printf("  Label: %s\n", Label)
 	fmt.Printf("  Plist: %s\n", plist)
-	fmt.Printf("  Logs:  %s\n", LogDir())
+	fmt.Printf("  Logs:  %s\n", LogFile())
 	fmt.Println()
 	fmt.Println("The service will run `granary run` every 2 hours.")
 	return nil
@@ -0,0 +1,63 @@
+// Searches the prebuilt index in search-index.js. Every word of the query
+// must appear in a meeting's title, series, attendees, notes or transcript.
+(function () {
+  var index = window.granarySearchIndex;
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var byDate = document.getElementById("by-date");
+  if (!index || !input || !results || !byDate) {
+    return;
+  }
+
+  var maxResults = 100;
+  input.hidden = false;
+
+  function item(entry) {
+    var li = document.createElement("li");
+    var date = document.createElement("span");
+    date.className = "date";
+    date.textContent = entry.d;
+    var link = document.createElement("a");
+    link.href = entry.u;
+    link.textContent = entry.t;
+    li.appendChild(date);
+    li.appendChild(document.createTextNode(" "));
+    li.appendChild(link);
+    if (entry.s) {
+      var series = document.createElement("span");
+      series.className = "series-name";
+      series.textContent = " · " + entry.s;
+      li.appendChild(series);
+    }
+    return li;
+  }
+
+  function search() {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    if (words.length === 0) {
+      results.hidden = true;
+      byDate.hidden = false;
+      return;
+    }
+
+    var found = 0;
+    for (var i = 0; i < index.length && found < maxResults; i++) {
+      var entry = index[i];
+      var haystack = (entry.t + " " + (entry.s || "")).toLowerCase() + " " + entry.x;
+      if (words.every(function (w) { return haystack.indexOf(w) !== -1; })) {
+        results.appendChild(item(entry));
+        found++;
+      }
+    }
+    if (found === 0) {
+      var none = document.createElement("li");
+      none.textContent = "No meetings found.";
+      results.appendChild(none);
+    }
+    results.hidden = false;
+    byDate.hidden = true;
+  }
+
+  input.addEventListener("input", search);
+})();
@@ -0,0 +1,255 @@
+package site
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// The markdown renderer supports what Granola writes in notes: headings,
+// paragraphs, nested bullet and ordered lists, blockquotes, code blocks,
+// horizontal rules, and bold, italic, strikethrough, code and link spans.
+// Everything is escaped before markup is added, so notes can't inject HTML.
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	rulePattern    = regexp.MustCompile(`^\s{0,3}((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	listPattern    = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+
+	codeSpanPattern = regexp.MustCompile("`([^`]+)`")
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(&lt;([^)]*?)&gt;\)|\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern     = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	italicPattern   = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*|\b_(\S(?:[^_]*?\S)?)_\b`)
+	strikePattern   = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+)
+
+// renderMarkdown converts markdown to HTML. Headings are moved down by
+// headingOffset levels so they nest under the page's own headings.
+func renderMarkdown(src string, headingOffset int) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	r := markdownRenderer{headingOffset: headingOffset}
+	return r.blocks(strings.Split(src, "\n"))
+}
+
+type markdownRenderer struct {
+	headingOffset int
+}
+
+// blocks renders a sequence of lines as block elements.
+func (r markdownRenderer) blocks(lines []string) string {
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			if lang != "" {
+				fmt.Fprintf(&b, "<pre><code class=\"language-%s\">%s</code></pre>\n", html.EscapeString(lang), html.EscapeString(strings.Join(code, "\n")))
+			} else {
+				fmt.Fprintf(&b, "<pre><code>%s</code></pre>\n", html.EscapeString(strings.Join(code, "\n")))
+			}
+
+		case headingPattern.MatchString(trimmed):
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := min(len(m[1])+r.headingOffset, 6)
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, renderInline(m[2]), level)
+			i++
+
+		case rulePattern.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+				i++
+			}
+			fmt.Fprintf(&b, "<blockquote>\n%s</blockquote>\n", r.blocks(quoted))
+
+		case listPattern.MatchString(line):
+			var list string
+			list, i = r.list(lines, i)
+			b.WriteString(list)
+
+		default:
+			var para []string
+			for i < len(lines) && r.continuesParagraph(lines[i]) {
+				para = append(para, lines[i])
+				i++
+			}
+			fmt.Fprintf(&b, "<p>%s</p>\n", renderParagraph(para))
+		}
+	}
+	return b.String()
+}
+
+// continuesParagraph reports whether line belongs to the paragraph above it.
+func (r markdownRenderer) continuesParagraph(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" &&
+		!strings.HasPrefix(trimmed, "```") &&
+		!strings.HasPrefix(trimmed, ">") &&
+		!headingPattern.MatchString(trimmed) &&
+		!rulePattern.MatchString(line) &&
+		!listPattern.MatchString(line)
+}
+
+// list renders the list starting at lines[start] and returns the index of
+// the first line after it. Lines indented past an item's marker belong to
+// that item, which is how nested lists are found.
+func (r markdownRenderer) list(lines []string, start int) (string, int) {
+	first := listPattern.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	ordered := isOrderedMarker(first[2])
+
+	tag := "ul"
+	open := "<ul>\n"
+	if ordered {
+		tag = "ol"
+		open = "<ol>\n"
+		if n := strings.TrimRight(first[2], ".)"); n != "1" {
+			open = fmt.Sprintf("<ol start=\"%s\">\n", strings.TrimLeft(n, "0"))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(open)
+	i := start
+	for i < len(lines) {
+		m := listPattern.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) != indent || isOrderedMarker(m[2]) != ordered {
+			break
+		}
+
+		// The item's own text, then any lines indented under it
+		contentIndent := indent + len(m[2]) + 1
+		text := []string{m[3]}
+		var children []string
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) > indent {
+					children = append(children, "")
+					i++
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) <= indent {
+				break
+			}
+			if len(children) == 0 && !listPattern.MatchString(line) {
+				text = append(text, line)
+			} else {
+				children = append(children, dedent(line, contentIndent))
+			}
+			i++
+		}
+
+		b.WriteString("<li>")
+		b.WriteString(renderParagraph(text))
+		if len(children) > 0 {
+			b.WriteString("\n")
+			b.WriteString(r.blocks(children))
+		}
+		b.WriteString("</li>\n")
+
+		// A blank line between items doesn't end the list
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			if m := listPattern.FindStringSubmatch(lines[i+1]); m != nil && len(m[1]) == indent && isOrderedMarker(m[2]) == ordered {
+				i++
+			}
+		}
+	}
+	fmt.Fprintf(&b, "</%s>\n", tag)
+	return b.String(), i
+}
+
+// isOrderedMarker reports whether a list marker is numbered.
+func isOrderedMarker(marker string) bool {
+	return strings.HasSuffix(marker, ".") || strings.HasSuffix(marker, ")")
+}
+
+// leadingSpaces counts the spaces at the start of line.
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// dedent removes up to n leading spaces from line.
+func dedent(line string, n int) string {
+	return line[min(n, leadingSpaces(line)):]
+}
+
+// renderParagraph renders the lines of a paragraph, keeping hard line
+// breaks written as two trailing spaces.
+func renderParagraph(lines []string) string {
+	var parts []string
+	for i, line := range lines {
+		hardBreak := strings.HasSuffix(line, "  ") && i < len(lines)-1
+		part := renderInline(strings.TrimSpace(line))
+		if hardBreak {
+			part += "<br>"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// renderInline escapes text and converts inline markdown to HTML.
+func renderInline(text string) string {
+	text = html.EscapeString(text)
+
+	// Code spans are set aside first so nothing inside them is formatted
+	var codes []string
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(s string) string {
+		codes = append(codes, "<code>"+codeSpanPattern.FindStringSubmatch(s)[1]+"</code>")
+		return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+	})
+
+	text = linkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := linkPattern.FindStringSubmatch(s)
+		label, href := m[1], m[2]
+		if label == "" {
+			label, href = m[3], m[4]
+		}
+		if !safeHref(href) {
+			return label
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, href, label)
+	})
+	text = boldPattern.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = italicPattern.ReplaceAllString(text, "<em>$1$2</em>")
+	text = strikePattern.ReplaceAllString(text, "<del>$1</del>")
+
+	for i, code := range codes {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), code, 1)
+	}
+	return text
+}
+
+// safeHref reports whether an (already escaped) link destination is safe to
+// put in an href: web and mail links, anchors and relative paths.
+func safeHref(href string) bool {
+	lower := strings.ToLower(html.UnescapeString(href))
+	if i := strings.IndexAny(lower, ":/?#"); i >= 0 && lower[i] == ':' {
+		return strings.HasPrefix(lower, "http:") || strings.HasPrefix(lower, "https:") || strings.HasPrefix(lower, "mailto:")
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package site
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "headings are moved down",
+			src:  "# Summary",
+			want: "<h3>Summary</h3>\n",
+		},
+		{
+			name: "paragraphs and inline formatting",
+			src:  "Some **bold**, *italic*, ~~gone~~ and `code`\nstill the same paragraph",
+			want: "<p>Some <strong>bold</strong>, <em>italic</em>, <del>gone</del> and <code>code</code>\nstill the same paragraph</p>\n",
+		},
+		{
+			name: "nested lists",
+			src:  "- One\n  - Nested\n- Two\n\n3. Three\n4. Four",
+			want: "<ul>\n<li>One\n<ul>\n<li>Nested</li>\n</ul>\n</li>\n<li>Two</li>\n</ul>\n<ol start=\"3\">\n<li>Three</li>\n<li>Four</li>\n</ol>\n",
+		},
+		{
+			name: "blockquotes, rules and code blocks",
+			src:  "> Quoted\n\n***\n\n```go\nx := <-ch\n```",
+			want: "<blockquote>\n<p>Quoted</p>\n</blockquote>\n<hr>\n<pre><code class=\"language-go\">x := &lt;-ch</code></pre>\n",
+		},
+		{
+			name: "links",
+			src:  "[Docs](https://example.com/a?b=1&c=2) and [file](<../x y.md>)",
+			want: "<p><a href=\"https://example.com/a?b=1&amp;c=2\">Docs</a> and <a href=\"../x y.md\">file</a></p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(tt.src, 2); got != tt.want {
+				t.Errorf("renderMarkdown(%q) =\n%s\nwant\n%s", tt.src, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("escapes HTML and unsafe links", func(t *testing.T) {
+		got := renderMarkdown("<script>alert(1)</script> [x](javascript:alert(1))", 0)
+		if strings.Contains(got, "<script>") || strings.Contains(got, "javascript:") && strings.Contains(got, "href") {
+			t.Errorf("Expected HTML and script links to be neutralized, got %q", got)
+		}
+	})
+}
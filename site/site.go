@@ -0,0 +1,430 @@
+// Package site renders the meeting archive as a static HTML site that works
+// offline: every page, style and script is written to the output directory,
+// and nothing is loaded from the network.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wassimk/granary/exporter"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed assets
+var assetFS embed.FS
+
+// Options configures Build.
+type Options struct {
+	// OutDir is the directory the site is written to.
+	OutDir string
+	// Title is shown at the top of every page.
+	Title string
+	// Logger receives progress messages. A nil Logger discards them.
+	Logger *slog.Logger
+}
+
+// DefaultTitle is the site title used when Options.Title is empty.
+const DefaultTitle = "Meetings"
+
+// Result describes a built site.
+type Result struct {
+	Meetings int
+	Series   int
+	// Removed counts pages of meetings that are no longer in the archive.
+	Removed int
+}
+
+// meetingsDir is the folder inside the site that meeting pages go in.
+const meetingsDir = "meetings"
+
+// searchIndexFile is the prebuilt search index. It is a script that assigns
+// the JSON index to a variable rather than a .json file, because browsers
+// refuse to fetch files from pages opened straight from disk.
+const searchIndexFile = "search-index.js"
+
+// templates are the page templates; see templates/layout.html for the
+// parts every page shares.
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"markdown": func(src string) template.HTML {
+		return template.HTML(renderMarkdown(src, 2))
+	},
+	"duration": formatDuration,
+}).ParseFS(templateFS, "templates/*.html"))
+
+// Site is a rendered site held in memory.
+type Site struct {
+	// Files maps slash-separated paths within the site to their contents.
+	Files map[string][]byte
+	// Pages maps meeting IDs to the path of their page.
+	Pages map[string]string
+	// Series is the number of recurring series.
+	Series int
+}
+
+// Render renders the site for meetings, which must be sorted by date: an
+// index by date, an index by series, a page per meeting, a search index and
+// the stylesheet and scripts. An empty title uses DefaultTitle.
+func Render(meetings []exporter.Meeting, title string) (*Site, error) {
+	if title == "" {
+		title = DefaultTitle
+	}
+
+	a := newArchive(meetings)
+	site := &Site{
+		Files:  make(map[string][]byte),
+		Pages:  make(map[string]string, len(a.meetings)),
+		Series: len(a.series),
+	}
+
+	render := func(path, name string, p page) error {
+		p.SiteTitle = title
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, name, p); err != nil {
+			return fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		site.Files[path] = buf.Bytes()
+		return nil
+	}
+
+	if err := render("index.html", "index.html", page{Data: a.indexPage()}); err != nil {
+		return nil, err
+	}
+	if err := render("series.html", "series.html", page{Title: "Series", Data: a.series}); err != nil {
+		return nil, err
+	}
+	for _, m := range a.meetings {
+		site.Pages[m.ID] = m.URL
+		if err := render(m.URL, "meeting.html", page{Title: m.Title, Root: "../", Data: m}); err != nil {
+			return nil, err
+		}
+	}
+
+	index, err := searchIndex(a.meetings)
+	if err != nil {
+		return nil, err
+	}
+	site.Files[searchIndexFile] = index
+
+	err = fs.WalkDir(assetFS, "assets", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := assetFS.ReadFile(path)
+		site.Files[path] = data
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assets: %w", err)
+	}
+
+	return site, nil
+}
+
+// Build renders the site for meetings, which must be sorted by date, and
+// writes it to opts.OutDir. Files that haven't changed are left alone, and
+// pages for meetings no longer in the archive are removed.
+func Build(meetings []exporter.Meeting, opts Options) (*Result, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	site, err := Render(meetings, opts.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(opts.OutDir, meetingsDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create site directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(site.Files))
+	for path := range site.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := writeIfChanged(filepath.Join(opts.OutDir, filepath.FromSlash(path)), site.Files[path]); err != nil {
+			return nil, err
+		}
+	}
+
+	pages := make(map[string]bool, len(site.Pages))
+	for _, url := range site.Pages {
+		pages[filepath.Base(url)] = true
+	}
+	removed, err := removeStalePages(filepath.Join(opts.OutDir, meetingsDir), pages)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("built site", "dir", opts.OutDir, "meetings", len(site.Pages), "series", site.Series)
+	return &Result{Meetings: len(site.Pages), Series: site.Series, Removed: removed}, nil
+}
+
+// page is the data every template is executed with.
+type page struct {
+	SiteTitle string
+	// Title is the page's own title, empty for the front page.
+	Title string
+	// Root is the path from the page back to the top of the site.
+	Root string
+	Data any
+}
+
+// writeIfChanged writes data to path unless the file already holds it, so
+// rebuilding an unchanged archive leaves modification times alone.
+func writeIfChanged(path string, data []byte) error {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// removeStalePages removes meeting pages in dir that aren't in keep.
+func removeStalePages(dir string, keep map[string]bool) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	removed := 0
+	for _, entry := range entries {
+		if keep[entry.Name()] || !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), ".html") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove stale page: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// archive is the meetings arranged for the site's pages.
+type archive struct {
+	meetings []*meetingPage
+	series   []*seriesPage
+}
+
+// meetingPage is the data for one meeting's page.
+type meetingPage struct {
+	exporter.Meeting
+	URL string
+	// DateLabel is the meeting's date and time, or "Unknown date".
+	DateLabel string
+	Lines     []transcriptLine
+	InSeries  *seriesPage
+	// Previous and Next are the neighbouring meetings in the series.
+	Previous, Next *meetingPage
+}
+
+// transcriptLine is one transcript entry with the class that colors it.
+type transcriptLine struct {
+	Speaker string
+	Class   string
+	Text    string
+}
+
+// seriesPage is a series as listed on the series index.
+type seriesPage struct {
+	Title    string
+	Anchor   string
+	Meetings []*meetingPage
+}
+
+// monthGroup is a month's meetings on the index by date.
+type monthGroup struct {
+	Month    string
+	Meetings []*meetingPage
+}
+
+// newArchive builds the pages for meetings.
+func newArchive(meetings []exporter.Meeting) *archive {
+	a := &archive{}
+	byID := make(map[string]*meetingPage, len(meetings))
+	used := make(map[string]bool, len(meetings))
+	for _, m := range meetings {
+		p := &meetingPage{
+			Meeting:   m,
+			URL:       meetingsDir + "/" + pageName(m.ID, used),
+			DateLabel: "Unknown date",
+		}
+		if p.Title == "" {
+			p.Title = "Untitled"
+		}
+		if !m.Date.IsZero() {
+			p.DateLabel = m.Date.Format("2006-01-02 15:04")
+		}
+		for _, entry := range m.Transcript {
+			text := strings.TrimSpace(entry.Text)
+			if text != "" {
+				p.Lines = append(p.Lines, transcriptLine{
+					Speaker: exporter.SourceToSpeaker(entry.Source),
+					Class:   speakerClass(entry.Source),
+					Text:    text,
+				})
+			}
+		}
+		a.meetings = append(a.meetings, p)
+		byID[m.ID] = p
+	}
+
+	for _, group := range exporter.GroupSeries(meetings) {
+		s := &seriesPage{Title: group[len(group)-1].Title, Anchor: anchor(group[0].Series)}
+		if s.Title == "" {
+			s.Title = "Untitled"
+		}
+		var previous *meetingPage
+		for _, m := range group {
+			p := byID[m.ID]
+			p.InSeries = s
+			if previous != nil {
+				previous.Next = p
+				p.Previous = previous
+			}
+			previous = p
+			s.Meetings = append(s.Meetings, p)
+		}
+		a.series = append(a.series, s)
+	}
+	sort.SliceStable(a.series, func(i, j int) bool {
+		return strings.ToLower(a.series[i].Title) < strings.ToLower(a.series[j].Title)
+	})
+
+	return a
+}
+
+// indexPage groups meetings by month, newest first.
+func (a *archive) indexPage() []monthGroup {
+	var months []monthGroup
+	for i := len(a.meetings) - 1; i >= 0; i-- {
+		p := a.meetings[i]
+		month := "Unknown date"
+		if !p.Date.IsZero() {
+			month = p.Date.Format("January 2006")
+		}
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, monthGroup{Month: month})
+		}
+		months[len(months)-1].Meetings = append(months[len(months)-1].Meetings, p)
+	}
+	return months
+}
+
+// pageName returns a unique file name for a meeting's page.
+func pageName(id string, used map[string]bool) string {
+	base := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, id)
+	if base == "" {
+		base = "meeting"
+	}
+	name := base + ".html"
+	for n := 2; used[name]; n++ {
+		name = fmt.Sprintf("%s-%d.html", base, n)
+	}
+	used[name] = true
+	return name
+}
+
+// anchor returns a stable HTML id for a series key.
+func anchor(key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("series-%08x", h.Sum32())
+}
+
+// speakerClasses color speakers other than "Me" and "Them".
+const speakerClasses = 6
+
+// speakerClass returns the CSS class that colors a transcript source.
+func speakerClass(source string) string {
+	switch source {
+	case "microphone":
+		return "speaker-me"
+	case "system":
+		return "speaker-them"
+	}
+	h := fnv.New32a()
+	h.Write([]byte(source))
+	return fmt.Sprintf("speaker-%d", h.Sum32()%speakerClasses)
+}
+
+// formatDuration formats seconds as "1h 5m" or "12m", or "" for zero.
+func formatDuration(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	d := time.Duration(seconds) * time.Second
+	if h := int(d.Hours()); h > 0 {
+		return fmt.Sprintf("%dh %dm", h, int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", max(int(d.Minutes()), 1))
+}
+
+// searchEntry is one meeting in the search index. Keys are short because
+// the index holds every transcript.
+type searchEntry struct {
+	Title  string `json:"t"`
+	Date   string `json:"d"`
+	URL    string `json:"u"`
+	Series string `json:"s,omitempty"`
+	Text   string `json:"x"`
+}
+
+// searchIndex returns the search index script for meetings, newest first.
+func searchIndex(meetings []*meetingPage) ([]byte, error) {
+	entries := make([]searchEntry, 0, len(meetings))
+	for i := len(meetings) - 1; i >= 0; i-- {
+		p := meetings[i]
+		var text []string
+		for _, a := range p.Attendees {
+			text = append(text, a.Name, a.Email)
+		}
+		text = append(text, p.MyNotes, p.AINotes)
+		for _, line := range p.Lines {
+			text = append(text, line.Text)
+		}
+
+		entry := searchEntry{
+			Title: p.Title,
+			Date:  p.DateLabel,
+			URL:   p.URL,
+			Text:  strings.ToLower(strings.Join(strings.Fields(strings.Join(text, " ")), " ")),
+		}
+		if p.InSeries != nil {
+			entry.Series = p.InSeries.Title
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search index: %w", err)
+	}
+	script := append([]byte("window.granarySearchIndex = "), data...)
+	return append(script, ";\n"...), nil
+}
@@ -0,0 +1,178 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wassimk/granary/exporter"
+)
+
+func testMeetings() []exporter.Meeting {
+	day := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", s)
+		return t
+	}
+	return []exporter.Meeting{
+		{
+			ID: "sync-1", Title: "Weekly Sync", Date: day("2026-01-19 10:00"), Series: "event:sync",
+			HasNotes: true, AINotes: "## Decisions\n\n- Ship <it>",
+			Attendees: []exporter.Attendee{{Name: "Ada", Email: "ada@example.com", Organizer: true}},
+		},
+		{
+			ID: "one-off", Title: "Budget review", Date: day("2026-01-20 15:00"),
+			HasTranscript: true,
+			Transcript: []exporter.TranscriptEntry{
+				{Source: "microphone", Text: "Shall we start?"},
+				{Source: "system", Text: "Yes, the forecast is ready."},
+			},
+		},
+		{ID: "sync-2", Title: "Weekly Sync", Date: day("2026-02-02 10:00"), Series: "event:sync", HasNotes: true, MyNotes: "Follow up"},
+	}
+}
+
+func readSiteFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("Expected %s: %v", name, err)
+	}
+	return string(content)
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("writes pages, indexes and assets", func(t *testing.T) {
+		dir := t.TempDir()
+		result, err := Build(testMeetings(), Options{OutDir: dir})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Meetings != 3 || result.Series != 1 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+
+		index := readSiteFile(t, dir, "index.html")
+		feb := strings.Index(index, "<h2>February 2026</h2>")
+		jan := strings.Index(index, "<h2>January 2026</h2>")
+		if feb < 0 || jan < feb {
+			t.Errorf("Expected months newest first in index:\n%s", index)
+		}
+		if !strings.Contains(index, `<a href="meetings/one-off.html">Budget review</a>`) {
+			t.Errorf("Expected link to meeting page in index:\n%s", index)
+		}
+
+		series := readSiteFile(t, dir, "series.html")
+		first := strings.Index(series, "meetings/sync-1.html")
+		second := strings.Index(series, "meetings/sync-2.html")
+		if first < 0 || second < first || strings.Contains(series, "one-off") {
+			t.Errorf("Expected the series in date order without one-off meetings:\n%s", series)
+		}
+
+		for _, name := range []string{"assets/style.css", "assets/search.js", searchIndexFile} {
+			readSiteFile(t, dir, name)
+		}
+	})
+
+	t.Run("renders notes, attendees and colored transcripts", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, err := Build(testMeetings(), Options{OutDir: dir}); err != nil {
+			t.Fatal(err)
+		}
+
+		sync := readSiteFile(t, dir, "meetings/sync-1.html")
+		for _, want := range []string{
+			"<h4>Decisions</h4>",
+			"<li>Ship &lt;it&gt;</li>",
+			"Ada &lt;ada@example.com&gt; (organizer)",
+			`<a rel="next" href="../meetings/sync-2.html">`,
+			`href="../assets/style.css"`,
+		} {
+			if !strings.Contains(sync, want) {
+				t.Errorf("Expected %q in:\n%s", want, sync)
+			}
+		}
+
+		budget := readSiteFile(t, dir, "meetings/one-off.html")
+		for _, want := range []string{
+			`<p class="line speaker-me"><span class="speaker">Me</span> Shall we start?</p>`,
+			`<p class="line speaker-them"><span class="speaker">Them</span> Yes, the forecast is ready.</p>`,
+		} {
+			if !strings.Contains(budget, want) {
+				t.Errorf("Expected %q in:\n%s", want, budget)
+			}
+		}
+	})
+
+	t.Run("indexes text for search", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, err := Build(testMeetings(), Options{OutDir: dir}); err != nil {
+			t.Fatal(err)
+		}
+
+		index := readSiteFile(t, dir, searchIndexFile)
+		if !strings.HasPrefix(index, "window.granarySearchIndex = [") {
+			t.Fatalf("Unexpected search index: %s", index)
+		}
+		for _, want := range []string{`"u":"meetings/one-off.html"`, "the forecast is ready", `"s":"Weekly Sync"`, "ada@example.com"} {
+			if !strings.Contains(index, want) {
+				t.Errorf("Expected %q in search index: %s", want, index)
+			}
+		}
+	})
+
+	t.Run("loads nothing from the network", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, err := Build(testMeetings(), Options{OutDir: dir}); err != nil {
+			t.Fatal(err)
+		}
+
+		external := regexp.MustCompile(`(src|href)="(https?:)?//`)
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, _ := os.ReadFile(path)
+			if external.Match(content) || strings.Contains(string(content), "url(http") {
+				t.Errorf("Found an external reference in %s", path)
+			}
+			return nil
+		})
+	})
+
+	t.Run("removes pages of meetings no longer in the archive", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, err := Build(testMeetings(), Options{OutDir: dir}); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Build(testMeetings()[:2], Options{OutDir: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Removed != 1 {
+			t.Errorf("Expected 1 removed page, got %d", result.Removed)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "meetings", "sync-2.html")); !os.IsNotExist(err) {
+			t.Errorf("Expected stale page to be removed, got %v", err)
+		}
+	})
+}
+
+func TestPageName(t *testing.T) {
+	used := make(map[string]bool)
+	if got := pageName("abc-123", used); got != "abc-123.html" {
+		t.Errorf("Unexpected name: %q", got)
+	}
+	if got := pageName("abc/123", used); got != "abc123.html" {
+		t.Errorf("Expected unsafe characters removed, got %q", got)
+	}
+	if got := pageName("abc-123", used); got != "abc-123-2.html" {
+		t.Errorf("Expected a numbered name for a repeat, got %q", got)
+	}
+	if got := pageName("../", used); got != "meeting.html" {
+		t.Errorf("Expected a fallback name, got %q", got)
+	}
+}
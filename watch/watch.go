@@ -0,0 +1,221 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wassimk/granary/exporter"
+)
+
+// Default timings used by granary watch.
+const (
+	DefaultDebounce = 5 * time.Second
+	DefaultInterval = time.Second
+)
+
+// maxRetries bounds how often a failed change is retried before waiting for
+// the next write.
+const maxRetries = 5
+
+// Options configures Run.
+type Options struct {
+	// Dir is the Granola directory containing cache-v*.json files.
+	Dir string
+	// Debounce is how long writes must pause before a change is handled.
+	Debounce time.Duration
+	// Interval is the polling period, and the gap between the two stat calls
+	// that decide whether a cache file has finished being written.
+	Interval time.Duration
+	// Poll disables filesystem notifications and only polls.
+	Poll bool
+	// Logger receives progress messages. A nil Logger discards them.
+	Logger *slog.Logger
+}
+
+// Run watches opts.Dir for writes to cache files and calls onChange with the
+// latest cache path once a burst of writes has settled and the file has stopped
+// growing. If onChange fails, for example because the cache was still only
+// partly written, it is retried after another debounce period. Run blocks
+// until ctx is cancelled.
+func Run(ctx context.Context, opts Options, onChange func(path string) error) error {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	if opts.Poll {
+		go poll(ctx, opts.Dir, opts.Interval, notify)
+	} else if err := watchEvents(ctx, opts.Dir, notify); err != nil {
+		logger.Warn("filesystem notifications unavailable, polling instead", "error", err)
+		go poll(ctx, opts.Dir, opts.Interval, notify)
+	}
+
+	logger.Info("watching for cache changes", "dir", opts.Dir)
+
+	timer := time.NewTimer(0)
+	<-timer.C
+	retries := 0
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-changes:
+			retries = 0
+			timer.Reset(opts.Debounce)
+		case <-timer.C:
+			err := settle(ctx, opts.Dir, opts.Interval, onChange)
+			if err == nil || ctx.Err() != nil {
+				continue
+			}
+			if retries < maxRetries {
+				retries++
+				logger.Warn("cache not ready, will retry", "attempt", retries, "error", err)
+				timer.Reset(opts.Debounce)
+			} else {
+				logger.Error("giving up until the cache changes again", "error", err)
+			}
+		}
+	}
+}
+
+// settle waits until the latest cache file stops changing, then calls onChange.
+func settle(ctx context.Context, dir string, interval time.Duration, onChange func(path string) error) error {
+	path, err := exporter.FindCacheFileIn(dir)
+	if err != nil {
+		return err
+	}
+
+	previous, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		current, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if current.Size() == previous.Size() && current.ModTime().Equal(previous.ModTime()) {
+			break
+		}
+		previous = current
+	}
+
+	return onChange(path)
+}
+
+// watchEvents forwards filesystem events for cache files in dir to notify.
+func watchEvents(ctx context.Context, dir string, notify func()) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if exporter.IsCacheFile(event.Name) && event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					notify()
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// fileStamp is the part of a file's metadata that changes when it is written.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// poll checks the cache files in dir every interval and calls notify when any
+// of them appear, disappear or change.
+func poll(ctx context.Context, dir string, interval time.Duration, notify func()) {
+	previous := stampCacheFiles(dir)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := stampCacheFiles(dir)
+		if changed(previous, current) {
+			notify()
+		}
+		previous = current
+	}
+}
+
+// changed reports whether any cache file differs between two polls.
+func changed(previous, current map[string]fileStamp) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+	for path, stamp := range current {
+		before, ok := previous[path]
+		if !ok || before.size != stamp.size || !before.modTime.Equal(stamp.modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// stampCacheFiles returns the size and mtime of every cache file in dir.
+func stampCacheFiles(dir string) map[string]fileStamp {
+	matches, _ := filepath.Glob(filepath.Join(dir, "cache-v*.json"))
+
+	stamps := make(map[string]fileStamp, len(matches))
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil {
+			stamps[path] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return stamps
+}
@@ -0,0 +1,127 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the paths passed to onChange.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+	err   error
+}
+
+func (r *recorder) onChange(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, path)
+	return r.err
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+func startWatch(t *testing.T, opts Options, r *recorder) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, opts, r.onChange) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run returned error: %v", err)
+		}
+	})
+	// Give the watcher time to register before writing
+	time.Sleep(50 * time.Millisecond)
+}
+
+// writeInChunks simulates Granola writing a cache file over several writes.
+func writeInChunks(t *testing.T, path string, chunks ...string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, chunk := range chunks {
+		if _, err := f.WriteString(chunk); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func waitForCalls(t *testing.T, r *recorder, n int) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if r.count() >= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d calls, got %d", n, r.count())
+}
+
+func TestRun(t *testing.T) {
+	for _, poll := range []bool{false, true} {
+		name := "notify"
+		if poll {
+			name = "poll"
+		}
+
+		t.Run(name+" debounces a burst of writes", func(t *testing.T) {
+			dir := t.TempDir()
+			r := &recorder{}
+			startWatch(t, Options{Dir: dir, Debounce: 100 * time.Millisecond, Interval: 20 * time.Millisecond, Poll: poll}, r)
+
+			path := filepath.Join(dir, "cache-v6.json")
+			writeInChunks(t, path, `{"cache":`, `{"state":`, `{}}}`)
+
+			waitForCalls(t, r, 1)
+			time.Sleep(300 * time.Millisecond)
+			if r.count() != 1 {
+				t.Errorf("Expected a single call for one burst, got %d", r.count())
+			}
+			if r.calls[0] != path {
+				t.Errorf("Expected %s, got %s", path, r.calls[0])
+			}
+
+			writeInChunks(t, path, `{"cache":{}}`)
+			waitForCalls(t, r, 2)
+		})
+	}
+
+	t.Run("ignores files that are not caches", func(t *testing.T) {
+		dir := t.TempDir()
+		r := &recorder{}
+		startWatch(t, Options{Dir: dir, Debounce: 50 * time.Millisecond, Interval: 20 * time.Millisecond}, r)
+
+		writeInChunks(t, filepath.Join(dir, "supabase.json"), "{}")
+		time.Sleep(300 * time.Millisecond)
+
+		if r.count() != 0 {
+			t.Errorf("Expected no calls, got %d", r.count())
+		}
+	})
+
+	t.Run("retries when the cache is not ready", func(t *testing.T) {
+		dir := t.TempDir()
+		r := &recorder{err: errors.New("unexpected EOF")}
+		startWatch(t, Options{Dir: dir, Debounce: 50 * time.Millisecond, Interval: 20 * time.Millisecond}, r)
+
+		writeInChunks(t, filepath.Join(dir, "cache-v6.json"), `{"cache":`)
+
+		waitForCalls(t, r, 2)
+	})
+}